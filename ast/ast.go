@@ -0,0 +1,77 @@
+// Package ast defines the syntax tree produced by the parser.
+//
+// Keyword fields keep the spelling used in the source so that printers can
+// decide whether to normalize their case or leave them as written.
+package ast
+
+import "strings"
+
+// Node is implemented by every syntax tree node
+type Node interface {
+	node()
+}
+
+// Statement is a complete SQL statement
+type Statement interface {
+	Node
+	statementNode()
+}
+
+// Expr is a scalar or boolean expression
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// TableExpr is an item of a FROM clause
+type TableExpr interface {
+	Node
+	tableExprNode()
+}
+
+// Keyword is one or more keywords as written in the source, joined by single spaces
+type Keyword string
+
+// Upper returns the keyword in upper case
+func (k Keyword) Upper() string {
+	return strings.ToUpper(string(k))
+}
+
+// Is reports whether the keyword matches word (case-insensitive)
+func (k Keyword) Is(word string) bool {
+	return strings.EqualFold(string(k), word)
+}
+
+// Name is a possibly qualified identifier such as users, u.id or s."t"
+type Name struct {
+	Pos   int
+	Parts []string
+}
+
+// String returns the name with its parts joined by dots
+func (n *Name) String() string {
+	return strings.Join(n.Parts, ".")
+}
+
+// Comment is a line or block comment in the source text
+type Comment struct {
+	Text     string
+	Pos      int
+	Trailing bool // 注释之前同一行还有其他代码
+}
+
+// IsLine reports whether the comment runs to the end of its line, which is
+// every comment except a /* */ block, whatever line marker the dialect uses
+func (c *Comment) IsLine() bool {
+	return !strings.HasPrefix(c.Text, "/*")
+}
+
+// DataType is a type name such as VARCHAR(255) or NUMERIC(10, 2)
+type DataType struct {
+	Text string
+}
+
+func (*Name) node()     {}
+func (*Comment) node()  {}
+func (*DataType) node() {}
+func (*Name) exprNode() {}
@@ -0,0 +1,178 @@
+package ast
+
+// CreateTableStmt is CREATE [TEMPORARY] TABLE [IF NOT EXISTS] name (...)
+// followed by optional table options, PARTITION BY and AS query
+type CreateTableStmt struct {
+	Pos         int
+	Temporary   Keyword // 如 TEMPORARY、GLOBAL TEMPORARY、UNLOGGED
+	IfNotExists Keyword
+	Name        *Name
+	Columns     []*ColumnDef
+	Constraints []*TableConstraint
+	Rparen      int      // offset of the closing parenthesis, -1 without a column list
+	Options     *RawExpr // 表选项原文，如 ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+	PartitionBy *RawExpr // PARTITION BY 之后的原文
+	As          Keyword
+	Query       Statement
+}
+
+// ColumnDef is a column definition of CREATE TABLE; the type may be omitted
+// in dialects such as SQLite
+type ColumnDef struct {
+	Name        *Name
+	Type        *DataType
+	Constraints string // 列约束原文，如 NOT NULL DEFAULT 0
+}
+
+// TableConstraint is a table-level constraint such as PRIMARY KEY (id) or
+// CONSTRAINT fk FOREIGN KEY (a) REFERENCES t (b), kept verbatim
+type TableConstraint struct {
+	Pos  int
+	Text string
+}
+
+// AlterTableStmt is ALTER TABLE [IF EXISTS] [ONLY] name followed by one or
+// more comma-separated actions
+type AlterTableStmt struct {
+	Pos      int
+	IfExists Keyword
+	Only     Keyword
+	Name     *Name
+	Actions  []*AlterAction
+}
+
+// AlterAction is one action of ALTER TABLE such as ADD COLUMN, DROP
+// CONSTRAINT or RENAME TO; the operand is a column definition or kept verbatim
+type AlterAction struct {
+	Pos    int
+	Action Keyword // 如 ADD COLUMN、DROP CONSTRAINT IF EXISTS、RENAME TO，无法识别的操作为空
+	Column *ColumnDef
+	Text   string
+}
+
+// CreateIndexStmt is CREATE [UNIQUE] INDEX [name] ON table [USING method]
+// (columns) [INCLUDE (columns)] [WHERE predicate]
+type CreateIndexStmt struct {
+	Pos          int
+	Unique       Keyword // UNIQUE、FULLTEXT 或 SPATIAL
+	Concurrently Keyword
+	IfNotExists  Keyword
+	Name         *Name
+	On           Keyword
+	Table        *Name
+	Using        Keyword
+	Method       string
+	Columns      *RawExpr // 括号中的索引列原文
+	Include      []*Name
+	Options      *RawExpr // 如 WITH (fillfactor = 70)、TABLESPACE
+	Where        Expr
+}
+
+// CreateViewStmt is CREATE [OR REPLACE] [TEMPORARY] [MATERIALIZED] VIEW
+// name [(columns)] AS query
+type CreateViewStmt struct {
+	Pos          int
+	OrReplace    Keyword
+	Temporary    Keyword
+	Materialized Keyword
+	IfNotExists  Keyword
+	Name         *Name
+	Columns      []*Name
+	As           Keyword
+	Query        Statement
+	Options      *RawExpr // 查询之后的原文，如 WITH CHECK OPTION、WITH NO DATA
+}
+
+// SequenceStmt is CREATE SEQUENCE or ALTER SEQUENCE with its options
+type SequenceStmt struct {
+	Pos       int
+	Command   Keyword // CREATE 或 ALTER
+	Temporary Keyword
+	IfExists  Keyword // CREATE时为IF NOT EXISTS，ALTER时为IF EXISTS
+	Name      *Name
+	Options   []*RawExpr // 每个选项的原文，如 START WITH 1、NO CYCLE
+}
+
+// CreateSchemaStmt is CREATE SCHEMA [IF NOT EXISTS] [name] [AUTHORIZATION role]
+type CreateSchemaStmt struct {
+	Pos         int
+	IfNotExists Keyword
+	Name        *Name
+	Options     *RawExpr
+}
+
+// DropStmt is DROP object [IF EXISTS] name [, ...] [CASCADE | RESTRICT] for
+// tables, views, indexes, sequences and schemas
+type DropStmt struct {
+	Pos      int
+	Object   Keyword // 如 TABLE、MATERIALIZED VIEW、INDEX CONCURRENTLY
+	IfExists Keyword
+	Names    []*Name
+	On       Keyword // MySQL的DROP INDEX name ON table
+	Table    *Name
+	Behavior Keyword // CASCADE 或 RESTRICT
+}
+
+// CreateVirtualTableStmt is SQLite's CREATE VIRTUAL TABLE [IF NOT EXISTS]
+// name USING module [(args)]
+type CreateVirtualTableStmt struct {
+	Pos         int
+	IfNotExists Keyword
+	Name        *Name
+	Using       Keyword
+	Module      string
+	Args        []*RawExpr // 模块参数原文
+	Rparen      int        // offset of the closing parenthesis, -1 without arguments
+}
+
+// PragmaStmt is SQLite's PRAGMA [schema.]name [= value | (value)]
+type PragmaStmt struct {
+	Pos    int
+	Pragma Keyword
+	Name   *Name
+	Eq     bool     // 以 = value 形式赋值，否则为 (value)
+	Value  *RawExpr // 参数原文，查询时为nil
+}
+
+// AttachStmt is SQLite's ATTACH [DATABASE] file AS schema
+type AttachStmt struct {
+	Pos    int
+	Attach Keyword // ATTACH 或 ATTACH DATABASE
+	File   Expr
+	As     Keyword
+	Schema string
+}
+
+// DetachStmt is SQLite's DETACH [DATABASE] schema
+type DetachStmt struct {
+	Pos    int
+	Detach Keyword // DETACH 或 DETACH DATABASE
+	Schema string
+}
+
+func (*CreateTableStmt) node()        {}
+func (*ColumnDef) node()              {}
+func (*TableConstraint) node()        {}
+func (*AlterTableStmt) node()         {}
+func (*AlterAction) node()            {}
+func (*CreateIndexStmt) node()        {}
+func (*CreateViewStmt) node()         {}
+func (*SequenceStmt) node()           {}
+func (*CreateSchemaStmt) node()       {}
+func (*DropStmt) node()               {}
+func (*CreateVirtualTableStmt) node() {}
+func (*PragmaStmt) node()             {}
+func (*AttachStmt) node()             {}
+func (*DetachStmt) node()             {}
+
+func (*CreateTableStmt) statementNode()        {}
+func (*AlterTableStmt) statementNode()         {}
+func (*CreateIndexStmt) statementNode()        {}
+func (*CreateViewStmt) statementNode()         {}
+func (*SequenceStmt) statementNode()           {}
+func (*CreateSchemaStmt) statementNode()       {}
+func (*DropStmt) statementNode()               {}
+func (*CreateVirtualTableStmt) statementNode() {}
+func (*PragmaStmt) statementNode()             {}
+func (*AttachStmt) statementNode()             {}
+func (*DetachStmt) statementNode()             {}
@@ -0,0 +1,203 @@
+package ast
+
+// Literal is a string, number, boolean or NULL literal, including typed
+// literals such as DATE '2024-01-01'
+type Literal struct {
+	Pos   int
+	Value string
+}
+
+// Placeholder is a bind parameter such as ?, $1 or :name
+type Placeholder struct {
+	Pos   int
+	Value string
+}
+
+// BinaryExpr is an infix operation such as a = b, a AND b or a LIKE b
+type BinaryExpr struct {
+	Left  Expr
+	Op    string // 运算符原文，多个关键字以单个空格连接
+	Right Expr
+}
+
+// UnaryExpr is a prefix operation such as NOT a or -a
+type UnaryExpr struct {
+	Pos  int
+	Op   string
+	Expr Expr
+}
+
+// FuncCall is a function call such as COUNT(DISTINCT id)
+type FuncCall struct {
+	Name     *Name
+	Distinct Keyword // DISTINCT 或 ALL
+	Args     []Expr
+	Over     *OverClause
+}
+
+// OverClause is OVER name or OVER (window spec) after a window function call
+type OverClause struct {
+	Over Keyword
+	Name *Name
+	Spec *WindowSpec
+}
+
+// WindowSpec is a parenthesized window definition
+type WindowSpec struct {
+	Pos         int
+	Name        *Name // 引用的已命名窗口
+	PartitionBy []Expr
+	OrderBy     []*OrderItem
+	Frame       *WindowFrame
+	Rparen      int // offset of the closing parenthesis
+}
+
+// WindowFrame is ROWS/RANGE/GROUPS [BETWEEN start AND end] of a window spec
+type WindowFrame struct {
+	Pos     int
+	Units   Keyword // ROWS、RANGE 或 GROUPS
+	Between Keyword
+	Start   *FrameBound
+	And     Keyword
+	End     *FrameBound
+}
+
+// FrameBound is UNBOUNDED PRECEDING, CURRENT ROW or offset PRECEDING/FOLLOWING
+type FrameBound struct {
+	Offset Expr
+	Bound  Keyword // 如 UNBOUNDED PRECEDING、CURRENT ROW、FOLLOWING
+}
+
+// ParenExpr is an expression wrapped in parentheses
+type ParenExpr struct {
+	Pos    int
+	Expr   Expr
+	Rparen int // offset of the closing parenthesis
+}
+
+// TupleExpr is a parenthesized list such as (a, b)
+type TupleExpr struct {
+	Pos   int
+	Exprs []Expr
+}
+
+// SubqueryExpr is a parenthesized query used as an expression or table
+type SubqueryExpr struct {
+	Pos    int
+	Select Statement
+	Rparen int // offset of the closing parenthesis
+}
+
+// ExistsExpr is EXISTS (subquery)
+type ExistsExpr struct {
+	Pos      int
+	Exists   Keyword
+	Subquery *SubqueryExpr
+}
+
+// InExpr is expr [NOT] IN (list) or expr [NOT] IN (subquery)
+type InExpr struct {
+	Expr     Expr
+	Op       Keyword // IN 或 NOT IN
+	List     []Expr
+	Subquery *SubqueryExpr
+}
+
+// BetweenExpr is expr [NOT] BETWEEN low AND high
+type BetweenExpr struct {
+	Expr Expr
+	Op   Keyword // BETWEEN 或 NOT BETWEEN
+	Low  Expr
+	And  Keyword
+	High Expr
+}
+
+// CaseExpr is a simple or searched CASE expression
+type CaseExpr struct {
+	Pos     int
+	Operand Expr
+	Whens   []*WhenClause
+	Else    Expr
+	End     int // offset of the END keyword
+}
+
+// WhenClause is a WHEN ... THEN ... branch of a CASE expression
+type WhenClause struct {
+	Cond   Expr
+	Result Expr
+}
+
+// CastExpr is CAST(expr AS type) or the PostgreSQL shorthand expr::type
+type CastExpr struct {
+	Pos  int
+	Cast Keyword // 简写形式时为空
+	Expr Expr
+	As   Keyword // AS 或 ::
+	Type *DataType
+}
+
+// SubscriptExpr is an array subscript or slice such as arr[1] or arr[1:2]
+type SubscriptExpr struct {
+	Expr  Expr
+	Index string // 方括号内的原文
+}
+
+// OuterJoinExpr is a column followed by Oracle's (+) outer join marker
+type OuterJoinExpr struct {
+	Expr Expr
+}
+
+// ArrayExpr is an array constructor such as ARRAY[1, 2]
+type ArrayExpr struct {
+	Pos   int
+	Array Keyword
+	Elems []Expr
+}
+
+// RawExpr is a fragment the parser keeps verbatim, such as the arguments of
+// EXTRACT(YEAR FROM d)
+type RawExpr struct {
+	Pos  int
+	Text string
+}
+
+func (*Literal) node()       {}
+func (*Placeholder) node()   {}
+func (*BinaryExpr) node()    {}
+func (*UnaryExpr) node()     {}
+func (*FuncCall) node()      {}
+func (*OverClause) node()    {}
+func (*WindowSpec) node()    {}
+func (*WindowFrame) node()   {}
+func (*FrameBound) node()    {}
+func (*ParenExpr) node()     {}
+func (*TupleExpr) node()     {}
+func (*SubqueryExpr) node()  {}
+func (*ExistsExpr) node()    {}
+func (*InExpr) node()        {}
+func (*BetweenExpr) node()   {}
+func (*CaseExpr) node()      {}
+func (*WhenClause) node()    {}
+func (*CastExpr) node()      {}
+func (*SubscriptExpr) node() {}
+func (*OuterJoinExpr) node() {}
+func (*ArrayExpr) node()     {}
+func (*RawExpr) node()       {}
+
+func (*Literal) exprNode()       {}
+func (*Placeholder) exprNode()   {}
+func (*BinaryExpr) exprNode()    {}
+func (*UnaryExpr) exprNode()     {}
+func (*FuncCall) exprNode()      {}
+func (*ParenExpr) exprNode()     {}
+func (*TupleExpr) exprNode()     {}
+func (*SubqueryExpr) exprNode()  {}
+func (*ExistsExpr) exprNode()    {}
+func (*InExpr) exprNode()        {}
+func (*BetweenExpr) exprNode()   {}
+func (*CaseExpr) exprNode()      {}
+func (*CastExpr) exprNode()      {}
+func (*SubscriptExpr) exprNode() {}
+func (*OuterJoinExpr) exprNode() {}
+func (*ArrayExpr) exprNode()     {}
+func (*RawExpr) exprNode()       {}
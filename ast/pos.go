@@ -0,0 +1,149 @@
+package ast
+
+// Pos returns the byte offset where node starts in the source text, or -1
+// when it is unknown
+func Pos(node Node) int {
+	switch n := node.(type) {
+	case *Name:
+		return n.Pos
+	case *Comment:
+		return n.Pos
+	case *Literal:
+		return n.Pos
+	case *Placeholder:
+		return n.Pos
+	case *RawExpr:
+		return n.Pos
+	case *UnaryExpr:
+		return n.Pos
+	case *ParenExpr:
+		return n.Pos
+	case *TupleExpr:
+		return n.Pos
+	case *SubqueryExpr:
+		return n.Pos
+	case *ExistsExpr:
+		return n.Pos
+	case *CaseExpr:
+		return n.Pos
+	case *CastExpr:
+		return n.Pos
+	case *SubscriptExpr:
+		return Pos(n.Expr)
+	case *OuterJoinExpr:
+		return Pos(n.Expr)
+	case *ArrayExpr:
+		return n.Pos
+	case *BinaryExpr:
+		return Pos(n.Left)
+	case *FuncCall:
+		return Pos(n.Name)
+	case *InExpr:
+		return Pos(n.Expr)
+	case *BetweenExpr:
+		return Pos(n.Expr)
+	case *WhenClause:
+		return Pos(n.Cond)
+	case *WindowSpec:
+		return n.Pos
+	case *WindowFrame:
+		return n.Pos
+	case *WindowDef:
+		return Pos(n.Name)
+	case *WithStmt:
+		return n.Pos
+	case *CTE:
+		return Pos(n.Name)
+	case *SelectStmt:
+		return n.Pos
+	case *SetOpStmt:
+		return Pos(n.Left)
+	case *ParenStmt:
+		return n.Pos
+	case *InsertStmt:
+		return n.Pos
+	case *OnConflict:
+		return n.Pos
+	case *UpdateStmt:
+		return n.Pos
+	case *DeleteStmt:
+		return n.Pos
+	case *ReturningClause:
+		return n.Pos
+	case *MergeStmt:
+		return n.Pos
+	case *MergeWhen:
+		return n.Pos
+	case *CreateTableStmt:
+		return n.Pos
+	case *ColumnDef:
+		return Pos(n.Name)
+	case *TableConstraint:
+		return n.Pos
+	case *AlterTableStmt:
+		return n.Pos
+	case *AlterAction:
+		return n.Pos
+	case *CreateIndexStmt:
+		return n.Pos
+	case *CreateViewStmt:
+		return n.Pos
+	case *SequenceStmt:
+		return n.Pos
+	case *CreateSchemaStmt:
+		return n.Pos
+	case *DropStmt:
+		return n.Pos
+	case *CreateVirtualTableStmt:
+		return n.Pos
+	case *PragmaStmt:
+		return n.Pos
+	case *AttachStmt:
+		return n.Pos
+	case *DetachStmt:
+		return n.Pos
+	case *Top:
+		return n.Pos
+	case *DeclareStmt:
+		return n.Pos
+	case *VarDecl:
+		return n.Pos
+	case *SetStmt:
+		return n.Pos
+	case *BlockStmt:
+		return n.Pos
+	case *ExceptionHandler:
+		return n.Pos
+	case *IfStmt:
+		return n.Pos
+	case *CaseStmt:
+		return n.Pos
+	case *Branch:
+		return n.Pos
+	case *LoopStmt:
+		return n.Pos
+	case *RawStmt:
+		return n.Pos
+	case *Hierarchy:
+		return n.Pos
+	case *SelectItem:
+		return Pos(n.Expr)
+	case *OrderItem:
+		return Pos(n.Expr)
+	case *Assignment:
+		return Pos(n.Column)
+	case *TableName:
+		return Pos(n.Name)
+	case *IndexHint:
+		return n.Pos
+	case *DerivedTable:
+		return Pos(n.Subquery)
+	case *FuncTable:
+		return Pos(n.Func)
+	case *ParenTable:
+		return n.Pos
+	case *JoinExpr:
+		return Pos(n.Left)
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+package ast
+
+// DeclareStmt is a DECLARE of one or more variables, such as
+// DECLARE @id INT = 1, @name NVARCHAR(50)
+type DeclareStmt struct {
+	Pos     int
+	Declare Keyword
+	Vars    []*VarDecl
+}
+
+// VarDecl is one @name [AS] type [= value] entry of a DECLARE statement
+type VarDecl struct {
+	Pos   int
+	Name  string
+	As    Keyword
+	Type  *DataType
+	Value Expr
+}
+
+// SetStmt is SET @var = value, or SET followed by a session option such
+// as SET NOCOUNT ON
+type SetStmt struct {
+	Pos    int
+	Set    Keyword
+	Name   string // 变量名，设置会话选项时为空
+	Op     string // 赋值运算符，如 = 或 +=
+	Value  Expr
+	Option *RawExpr // 会话选项原文
+}
+
+// BlockStmt is a procedural block such as a PL/SQL anonymous block:
+// [DECLARE decls] BEGIN stmts [EXCEPTION handlers] END [label]
+type BlockStmt struct {
+	Pos          int
+	Declare      Keyword
+	Decls        []*RawExpr // 声明原文，不含结尾的分号
+	Begin        Keyword
+	BeginPos     int // offset of BEGIN
+	Body         []Statement
+	Exception    Keyword
+	ExceptionPos int // offset of EXCEPTION
+	Handlers     []*ExceptionHandler
+	End          Keyword
+	EndPos       int // offset of END
+	Label        string
+}
+
+// ExceptionHandler is a WHEN name [OR name] THEN stmts branch of an
+// EXCEPTION section
+type ExceptionHandler struct {
+	Pos   int
+	When  Keyword
+	Names *RawExpr
+	Then  Keyword
+	Body  []Statement
+}
+
+// IfStmt is a procedural IF cond THEN stmts [ELSIF cond THEN stmts]...
+// [ELSE stmts] END IF
+type IfStmt struct {
+	Pos      int
+	Branches []*Branch // IF分支及其后的ELSIF分支
+	Else     Keyword
+	ElsePos  int // offset of ELSE
+	ElseBody []Statement
+	End      Keyword // END IF
+	EndPos   int     // offset of END
+}
+
+// CaseStmt is a procedural CASE [operand] WHEN x THEN stmts ...
+// [ELSE stmts] END CASE, as opposed to a CASE expression
+type CaseStmt struct {
+	Pos      int
+	Case     Keyword
+	Operand  *RawExpr
+	Whens    []*Branch
+	Else     Keyword
+	ElsePos  int // offset of ELSE
+	ElseBody []Statement
+	End      Keyword // END CASE
+	EndPos   int     // offset of END
+}
+
+// Branch is one cond THEN stmts arm of an IF or CASE statement,
+// introduced by IF, ELSIF or WHEN
+type Branch struct {
+	Pos  int
+	Word Keyword // IF、ELSIF或WHEN
+	Cond *RawExpr
+	Then Keyword
+	Body []Statement
+}
+
+// LoopStmt is [WHILE cond | FOR iterator] LOOP stmts END LOOP [label]
+type LoopStmt struct {
+	Pos    int
+	Head   *RawExpr // WHILE或FOR部分的原文，基本循环为空
+	Loop   Keyword
+	Body   []Statement
+	End    Keyword // END LOOP
+	EndPos int     // offset of END
+	Label  string
+}
+
+// RawStmt is a statement inside a block that the parser keeps verbatim,
+// such as an assignment or a procedure call
+type RawStmt struct {
+	Pos  int
+	Text string
+}
+
+func (*DeclareStmt) node()      {}
+func (*VarDecl) node()          {}
+func (*SetStmt) node()          {}
+func (*BlockStmt) node()        {}
+func (*ExceptionHandler) node() {}
+func (*IfStmt) node()           {}
+func (*CaseStmt) node()         {}
+func (*Branch) node()           {}
+func (*LoopStmt) node()         {}
+func (*RawStmt) node()          {}
+
+func (*DeclareStmt) statementNode() {}
+func (*SetStmt) statementNode()     {}
+func (*BlockStmt) statementNode()   {}
+func (*IfStmt) statementNode()      {}
+func (*CaseStmt) statementNode()    {}
+func (*LoopStmt) statementNode()    {}
+func (*RawStmt) statementNode()     {}
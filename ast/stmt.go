@@ -0,0 +1,305 @@
+package ast
+
+// WithStmt is a statement preceded by WITH [RECURSIVE] common table expressions
+type WithStmt struct {
+	Pos       int
+	Recursive Keyword
+	CTEs      []*CTE
+	Stmt      Statement
+}
+
+// CTE is one name [(columns)] AS [[NOT] MATERIALIZED] (query) entry of a WITH clause
+type CTE struct {
+	Name         *Name
+	Columns      []*Name
+	As           Keyword
+	Materialized Keyword // MATERIALIZED 或 NOT MATERIALIZED
+	Subquery     *SubqueryExpr
+}
+
+// SetOpStmt combines two queries with UNION, INTERSECT or EXCEPT; ORDER BY
+// and LIMIT after the last query apply to the whole result
+type SetOpStmt struct {
+	Left    Statement
+	Op      Keyword // 如 UNION、UNION ALL、EXCEPT DISTINCT
+	Right   Statement
+	OrderBy []*OrderItem
+	Limit   *Limit
+}
+
+// ParenStmt is a parenthesized query used as an operand of a set operation
+type ParenStmt struct {
+	Pos    int
+	Stmt   Statement
+	Rparen int // offset of the closing parenthesis
+}
+
+// SelectStmt is a SELECT query
+type SelectStmt struct {
+	Pos        int
+	Distinct   Keyword // DISTINCT、DISTINCT ON 或 ALL
+	DistinctOn []Expr  // DISTINCT ON (...) 的表达式
+	Top        *Top
+	Columns    []*SelectItem
+	Into       []Expr // SELECT ... INTO 的目标变量
+	From       []TableExpr
+	Where      Expr
+	Hierarchy  *Hierarchy
+	GroupBy    []Expr
+	Having     Expr
+	Window     []*WindowDef
+	OrderBy    []*OrderItem
+	Siblings   Keyword // ORDER SIBLINGS BY 原文
+	Limit      *Limit
+}
+
+// Top is SQL Server's TOP (n) [PERCENT] [WITH TIES]
+type Top struct {
+	Pos      int
+	Top      Keyword
+	Count    Expr
+	Percent  Keyword
+	WithTies Keyword
+}
+
+// Hierarchy is Oracle's hierarchical query clause:
+// [START WITH cond] CONNECT BY [NOCYCLE] cond, in either order
+type Hierarchy struct {
+	Pos        int
+	StartWith  Expr
+	ConnectBy  Keyword // CONNECT BY 或 CONNECT BY NOCYCLE
+	Cond       Expr
+	StartFirst bool // START WITH 写在 CONNECT BY 之前
+}
+
+// SelectItem is an entry of the select list
+type SelectItem struct {
+	Expr  Expr
+	As    Keyword
+	Alias string
+}
+
+// WindowDef is a name AS (window spec) entry of a WINDOW clause
+type WindowDef struct {
+	Name *Name
+	As   Keyword
+	Spec *WindowSpec
+}
+
+// OrderItem is an entry of an ORDER BY list
+type OrderItem struct {
+	Expr      Expr
+	Direction Keyword // ASC 或 DESC
+	Nulls     Keyword // NULLS FIRST 或 NULLS LAST
+}
+
+// Limit is a LIMIT/OFFSET clause or the standard OFFSET n ROWS
+// FETCH {FIRST | NEXT} n ROWS ONLY
+type Limit struct {
+	Count      Expr
+	Offset     Expr
+	Comma      bool    // MySQL的 LIMIT offset, count 形式
+	OffsetRows Keyword // OFFSET n ROWS 中的 ROW 或 ROWS
+	Fetch      Keyword // FETCH FIRST 或 FETCH NEXT，此时Count为取出的行数（可以为空）
+	FetchRows  Keyword // 如 ROWS ONLY、ROW WITH TIES、PERCENT ROWS ONLY
+}
+
+// TableName is a named table in a FROM clause
+type TableName struct {
+	Name  *Name
+	As    Keyword
+	Alias string
+	Hints []*IndexHint
+}
+
+// IndexHint is a MySQL index hint such as USE INDEX (idx) or
+// FORCE INDEX FOR JOIN (a, b)
+type IndexHint struct {
+	Pos     int
+	Hint    Keyword  // 如 USE INDEX、IGNORE KEY FOR ORDER BY
+	Indexes *RawExpr // 括号中的索引名，可以为空
+}
+
+// DerivedTable is a [LATERAL] subquery in a FROM clause
+type DerivedTable struct {
+	Lateral  Keyword
+	Subquery *SubqueryExpr
+	As       Keyword
+	Alias    string
+	Columns  []*Name // 别名后的列名列表
+}
+
+// FuncTable is a [LATERAL] table-valued function call in a FROM clause
+type FuncTable struct {
+	Lateral Keyword
+	Func    *FuncCall
+	As      Keyword
+	Alias   string
+	Columns []*Name // 别名后的列名列表
+}
+
+// ParenTable is a parenthesized join in a FROM clause
+type ParenTable struct {
+	Pos    int
+	Table  TableExpr
+	Rparen int // offset of the closing parenthesis
+}
+
+// JoinExpr joins two table expressions
+type JoinExpr struct {
+	Left      TableExpr
+	Type      Keyword // 如 JOIN、LEFT OUTER JOIN、CROSS JOIN
+	Right     TableExpr
+	Condition *JoinCondition
+}
+
+// JoinCondition is the ON or USING part of a join
+type JoinCondition struct {
+	Keyword Keyword // ON 或 USING
+	On      Expr
+	Using   []*Name
+}
+
+// InsertStmt is an INSERT statement whose rows come from VALUES, a query
+// or DEFAULT VALUES
+type InsertStmt struct {
+	Pos           int
+	Insert        Keyword // 如 INSERT、INSERT IGNORE、REPLACE
+	Table         *Name
+	Columns       []*Name
+	Values        [][]Expr
+	Query         Statement
+	DefaultValues Keyword
+	OnConflict    *OnConflict
+	Output        *ReturningClause // SQL Server的OUTPUT子句，位于VALUES或查询之前
+	Returning     *ReturningClause
+}
+
+// OnConflict is PostgreSQL's ON CONFLICT [target] DO NOTHING | DO UPDATE SET
+// ... [WHERE ...] or MySQL's ON DUPLICATE KEY UPDATE ...
+type OnConflict struct {
+	Pos          int
+	Clause       Keyword  // ON CONFLICT 或 ON DUPLICATE KEY UPDATE
+	Target       *RawExpr // 括号中的冲突目标原文
+	TargetWhere  Expr     // 部分唯一索引的谓词
+	OnConstraint Keyword
+	Constraint   *Name
+	Action       Keyword // DO NOTHING 或 DO UPDATE SET
+	Set          []*Assignment
+	Where        Expr
+}
+
+// UpdateStmt is an UPDATE statement; Table may be a join, and From holds
+// the tables of PostgreSQL/SQL Server UPDATE ... FROM
+type UpdateStmt struct {
+	Pos       int
+	Conflict  Keyword // SQLite的 OR ROLLBACK/ABORT/REPLACE/FAIL/IGNORE
+	Table     TableExpr
+	Set       []*Assignment
+	Output    *ReturningClause
+	From      []TableExpr
+	Where     Expr
+	OrderBy   []*OrderItem
+	Limit     *Limit
+	Returning *ReturningClause
+}
+
+// Assignment is a column = value entry of a SET clause
+type Assignment struct {
+	Column *Name
+	Value  Expr
+}
+
+// DeleteStmt is a DELETE statement. MySQL's multi-table DELETE t1, t2 FROM
+// ... has Targets and From instead of Table; SQL Server's DELETE FROM t FROM
+// ... has both Table and From
+type DeleteStmt struct {
+	Pos       int
+	Targets   []*Name
+	Table     TableExpr
+	Output    *ReturningClause
+	From      []TableExpr
+	Using     []TableExpr
+	Where     Expr
+	OrderBy   []*OrderItem
+	Limit     *Limit
+	Returning *ReturningClause
+}
+
+// MergeStmt is MERGE INTO target USING source ON condition followed by
+// WHEN [NOT] MATCHED branches
+type MergeStmt struct {
+	Pos       int
+	Target    TableExpr
+	Source    TableExpr
+	On        Expr
+	Whens     []*MergeWhen
+	Output    *ReturningClause
+	Returning *ReturningClause
+}
+
+// MergeWhen is a WHEN [NOT] MATCHED [BY SOURCE | BY TARGET] [AND condition]
+// THEN action branch of MERGE
+type MergeWhen struct {
+	Pos           int
+	Match         Keyword // 如 MATCHED、NOT MATCHED、NOT MATCHED BY SOURCE
+	Cond          Expr
+	Action        Keyword // UPDATE SET、DELETE、INSERT 或 DO NOTHING
+	Set           []*Assignment
+	Columns       []*Name
+	Values        []Expr
+	DefaultValues Keyword
+}
+
+// ReturningClause is RETURNING items or SQL Server's OUTPUT items
+// [INTO target [(columns)]] of a DML statement
+type ReturningClause struct {
+	Pos         int
+	Keyword     Keyword // RETURNING 或 OUTPUT
+	Columns     []*SelectItem
+	Into        Keyword
+	Target      *Name
+	TargetNames []*Name
+}
+
+func (*SelectStmt) node()      {}
+func (*SetOpStmt) node()       {}
+func (*ParenStmt) node()       {}
+func (*WithStmt) node()        {}
+func (*CTE) node()             {}
+func (*SelectItem) node()      {}
+func (*WindowDef) node()       {}
+func (*OrderItem) node()       {}
+func (*Limit) node()           {}
+func (*Top) node()             {}
+func (*Hierarchy) node()       {}
+func (*TableName) node()       {}
+func (*IndexHint) node()       {}
+func (*DerivedTable) node()    {}
+func (*FuncTable) node()       {}
+func (*ParenTable) node()      {}
+func (*JoinExpr) node()        {}
+func (*JoinCondition) node()   {}
+func (*InsertStmt) node()      {}
+func (*OnConflict) node()      {}
+func (*UpdateStmt) node()      {}
+func (*Assignment) node()      {}
+func (*DeleteStmt) node()      {}
+func (*ReturningClause) node() {}
+func (*MergeStmt) node()       {}
+func (*MergeWhen) node()       {}
+
+func (*WithStmt) statementNode()   {}
+func (*SelectStmt) statementNode() {}
+func (*SetOpStmt) statementNode()  {}
+func (*ParenStmt) statementNode()  {}
+func (*InsertStmt) statementNode() {}
+func (*UpdateStmt) statementNode() {}
+func (*DeleteStmt) statementNode() {}
+func (*MergeStmt) statementNode()  {}
+
+func (*TableName) tableExprNode()    {}
+func (*DerivedTable) tableExprNode() {}
+func (*FuncTable) tableExprNode()    {}
+func (*ParenTable) tableExprNode()   {}
+func (*JoinExpr) tableExprNode()     {}
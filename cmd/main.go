@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"io"
@@ -9,12 +8,22 @@ import (
 	"strings"
 
 	sqlformatter "github.com/BruceDu521/sql-formatter"
+	"github.com/BruceDu521/sql-formatter/dialect"
 )
 
 var (
 	// Command line flags
 	indentSize   = flag.Int("indent", 2, "Number of spaces for indentation")
 	keywordUpper = flag.Bool("uppercase", true, "Use uppercase for keywords")
+	blankLines   = flag.Int("blank-lines", 1, "Number of blank lines between statements")
+	caseWidth    = flag.Int("case-width", 0, "Keep CASE expressions up to this width on one line (0: always break)")
+	trailingOps  = flag.Bool("trailing-operators", false, "Put AND/OR at the end of lines instead of the start")
+	windowWidth  = flag.Int("window-width", 60, "Keep window specifications up to this width on one line (0: always break)")
+	joinNewline  = flag.Bool("join-newline", false, "Put ON/USING on its own line below the joined table")
+	splitJoin    = flag.Bool("split-join", false, "Break ON conditions on AND/OR")
+	alignColumns = flag.Bool("align-columns", false, "Align column names, types and constraints in CREATE TABLE")
+	semicolon    = flag.String("semicolon", "preserve", "Semicolon after the last statement: preserve, always or never")
+	dialectName  = flag.String("dialect", "generic", "SQL dialect of the input: "+strings.Join(dialect.Names(), ", "))
 	inputFile    = flag.String("input", "", "Input SQL file")
 	outputFile   = flag.String("output", "", "Output file")
 	sqlString    = flag.String("sql", "", "SQL statement to format")
@@ -33,6 +42,32 @@ func main() {
 	formatter := sqlformatter.NewFormatter()
 	formatter.IndentSize = *indentSize
 	formatter.KeywordUpper = *keywordUpper
+	formatter.BlankLines = *blankLines
+	formatter.CaseInlineWidth = *caseWidth
+	formatter.WindowInlineWidth = *windowWidth
+	formatter.JoinConditionNewline = *joinNewline
+	formatter.SplitJoinConditions = *splitJoin
+	formatter.AlignColumns = *alignColumns
+	if *trailingOps {
+		formatter.LogicalOperators = sqlformatter.OperatorTrailing
+	}
+	switch *semicolon {
+	case "preserve":
+		formatter.Semicolon = sqlformatter.SemicolonPreserve
+	case "always":
+		formatter.Semicolon = sqlformatter.SemicolonAlways
+	case "never":
+		formatter.Semicolon = sqlformatter.SemicolonNever
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid -semicolon value %q\n", *semicolon)
+		os.Exit(1)
+	}
+	d, ok := dialect.Lookup(*dialectName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown -dialect %q (available: %s)\n", *dialectName, strings.Join(dialect.Names(), ", "))
+		os.Exit(1)
+	}
+	formatter.Dialect = d
 
 	var sql string
 	var err error
@@ -98,6 +133,18 @@ Options:
   -output string   Output file
   -indent int      Number of spaces for indentation (default: 2)
   -uppercase       Use uppercase for keywords (default: true)
+  -blank-lines int Number of blank lines between statements (default: 1)
+  -case-width int  Keep CASE expressions up to this width on one line (default: 0, always break)
+  -trailing-operators
+                   Put AND/OR at the end of lines instead of the start (default: false)
+  -window-width int
+                   Keep window specifications up to this width on one line (default: 60, 0 always breaks)
+  -join-newline    Put ON/USING on its own line below the joined table (default: false)
+  -split-join      Break ON conditions on AND/OR (default: false)
+  -align-columns   Align column names, types and constraints in CREATE TABLE (default: false)
+  -semicolon string
+                   Semicolon after the last statement: preserve, always or never (default: preserve)
+  -dialect string  SQL dialect of the input: %s (default: generic)
   -help            Show help information
 
 Examples:
@@ -106,7 +153,7 @@ Examples:
   sqlformatter -input input.sql -output output.sql
   sqlformatter "select u.id, u.name from users u where u.age > 25"
 
-`)
+`, strings.Join(dialect.Names(), ", "))
 }
 
 // readFromStdin reads from standard input
@@ -122,19 +169,13 @@ func readFromStdin() (string, error) {
 		fmt.Print("Enter SQL statement (press Ctrl+D to finish):\n")
 	}
 
-	var result strings.Builder
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for scanner.Scan() {
-		result.WriteString(scanner.Text())
-		result.WriteString(" ")
-	}
-
-	if err := scanner.Err(); err != nil {
+	// Keep line breaks, line comments end at them
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
 		return "", err
 	}
 
-	return strings.TrimSpace(result.String()), nil
+	return strings.TrimSpace(string(content)), nil
 }
 
 // readFromFile reads from a file
@@ -0,0 +1,285 @@
+package sqlformatter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// formatCreateTableStatement 格式化CREATE TABLE语句，每个列定义和表级约束各占一行
+func (p *printer) formatCreateTableStatement(stmt *ast.CreateTableStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("CREATE"))
+	if stmt.Temporary != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Temporary)))
+	}
+	result.WriteString(" " + p.keyword("TABLE"))
+	if stmt.IfNotExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfNotExists)))
+	}
+	result.WriteString(" " + stmt.Name.String())
+
+	// 列定义在前，表级约束在后
+	if stmt.Rparen >= 0 {
+		lines := p.formatColumnDefs(stmt.Columns)
+		nodes := make([]ast.Node, 0, len(lines)+len(stmt.Constraints))
+		for _, column := range stmt.Columns {
+			nodes = append(nodes, column)
+		}
+		for _, constraint := range stmt.Constraints {
+			lines = append(lines, constraint.Text)
+			nodes = append(nodes, constraint)
+		}
+		result.WriteString(" (")
+		for i, line := range lines {
+			if i > 0 {
+				result.WriteString(",")
+			}
+			result.WriteString(p.newline(level+1, ast.Pos(nodes[i])) + line)
+		}
+		result.WriteString(p.newline(level, stmt.Rparen) + ")")
+	}
+
+	if stmt.Options != nil {
+		result.WriteString(" " + stmt.Options.Text)
+	}
+	if stmt.PartitionBy != nil {
+		result.WriteString(p.newline(level, stmt.PartitionBy.Pos) + p.keyword("PARTITION BY") + " " + stmt.PartitionBy.Text)
+	}
+	if stmt.Query != nil {
+		result.WriteString(" " + string(stmt.As))
+		result.WriteString(p.newline(level, ast.Pos(stmt.Query)) + p.formatSQL(stmt.Query, level))
+	}
+
+	return result.String()
+}
+
+// formatAlterTableStatement 格式化ALTER TABLE语句，每个操作各占一行并缩进一级
+func (p *printer) formatAlterTableStatement(stmt *ast.AlterTableStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("ALTER TABLE"))
+	if stmt.IfExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfExists)))
+	}
+	if stmt.Only != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Only)))
+	}
+	result.WriteString(" " + stmt.Name.String())
+
+	for i, action := range stmt.Actions {
+		if i > 0 {
+			result.WriteString(",")
+		}
+		result.WriteString(p.newline(level+1, action.Pos))
+		var parts []string
+		if action.Action != "" {
+			parts = append(parts, p.keyword(string(action.Action)))
+		}
+		if action.Column != nil {
+			parts = append(parts, p.formatColumnDefs([]*ast.ColumnDef{action.Column})[0])
+		} else {
+			parts = append(parts, action.Text)
+		}
+		result.WriteString(strings.Join(parts, " "))
+	}
+
+	return result.String()
+}
+
+// formatCreateIndexStatement 格式化CREATE INDEX语句，INCLUDE和WHERE各占一行
+func (p *printer) formatCreateIndexStatement(stmt *ast.CreateIndexStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("CREATE"))
+	if stmt.Unique != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Unique)))
+	}
+	result.WriteString(" " + p.keyword("INDEX"))
+	if stmt.Concurrently != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Concurrently)))
+	}
+	if stmt.IfNotExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfNotExists)))
+	}
+	if stmt.Name != nil {
+		result.WriteString(" " + stmt.Name.String())
+	}
+	result.WriteString(" " + string(stmt.On) + " " + stmt.Table.String())
+	if stmt.Using != "" {
+		result.WriteString(" " + string(stmt.Using) + " " + stmt.Method)
+	}
+	result.WriteString(" (" + stmt.Columns.Text + ")")
+
+	if len(stmt.Include) > 0 {
+		result.WriteString(p.newline(level, ast.Pos(stmt.Include[0])) + p.keyword("INCLUDE"))
+		result.WriteString(" (" + p.formatNames(stmt.Include) + ")")
+	}
+	if stmt.Options != nil {
+		result.WriteString(" " + stmt.Options.Text)
+	}
+	if stmt.Where != nil {
+		result.WriteString(p.formatConditionClause("WHERE", stmt.Where, level))
+	}
+
+	return result.String()
+}
+
+// formatCreateViewStatement 格式化CREATE VIEW语句，视图查询从下一行开始
+func (p *printer) formatCreateViewStatement(stmt *ast.CreateViewStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("CREATE"))
+	for _, kw := range []ast.Keyword{stmt.OrReplace, stmt.Temporary, stmt.Materialized} {
+		if kw != "" {
+			result.WriteString(" " + p.keyword(string(kw)))
+		}
+	}
+	result.WriteString(" " + p.keyword("VIEW"))
+	if stmt.IfNotExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfNotExists)))
+	}
+	result.WriteString(" " + stmt.Name.String())
+	if len(stmt.Columns) > 0 {
+		result.WriteString(" (" + p.formatNames(stmt.Columns) + ")")
+	}
+	result.WriteString(" " + string(stmt.As))
+	result.WriteString(p.newline(level, ast.Pos(stmt.Query)) + p.formatSQL(stmt.Query, level))
+	if stmt.Options != nil {
+		result.WriteString(p.newline(level, stmt.Options.Pos) + stmt.Options.Text)
+	}
+
+	return result.String()
+}
+
+// formatSequenceStatement 格式化CREATE/ALTER SEQUENCE语句，每个选项各占一行
+func (p *printer) formatSequenceStatement(stmt *ast.SequenceStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword(string(stmt.Command)))
+	if stmt.Temporary != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Temporary)))
+	}
+	result.WriteString(" " + p.keyword("SEQUENCE"))
+	if stmt.IfExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfExists)))
+	}
+	result.WriteString(" " + stmt.Name.String())
+	for _, option := range stmt.Options {
+		result.WriteString(p.newline(level+1, option.Pos) + option.Text)
+	}
+
+	return result.String()
+}
+
+// formatCreateSchemaStatement 格式化CREATE SCHEMA语句
+func (p *printer) formatCreateSchemaStatement(stmt *ast.CreateSchemaStmt) string {
+	result := p.keyword("CREATE SCHEMA")
+	if stmt.IfNotExists != "" {
+		result += " " + p.keyword(string(stmt.IfNotExists))
+	}
+	if stmt.Name != nil {
+		result += " " + stmt.Name.String()
+	}
+	if stmt.Options != nil {
+		result += " " + stmt.Options.Text
+	}
+	return result
+}
+
+// formatDropStatement 格式化DROP语句
+func (p *printer) formatDropStatement(stmt *ast.DropStmt) string {
+	result := p.keyword("DROP") + " " + p.keyword(string(stmt.Object))
+	if stmt.IfExists != "" {
+		result += " " + p.keyword(string(stmt.IfExists))
+	}
+	result += " " + p.formatNames(stmt.Names)
+	if stmt.Table != nil {
+		result += " " + string(stmt.On) + " " + stmt.Table.String()
+	}
+	if stmt.Behavior != "" {
+		result += " " + p.keyword(string(stmt.Behavior))
+	}
+	return result
+}
+
+// formatCreateVirtualTableStatement 格式化CREATE VIRTUAL TABLE语句，模块参数与列定义一样每个独占一行
+func (p *printer) formatCreateVirtualTableStatement(stmt *ast.CreateVirtualTableStmt, level int) string {
+	var result strings.Builder
+	result.WriteString(p.keyword("CREATE VIRTUAL TABLE"))
+	if stmt.IfNotExists != "" {
+		result.WriteString(" " + p.keyword(string(stmt.IfNotExists)))
+	}
+	result.WriteString(" " + stmt.Name.String() + " " + p.keyword(string(stmt.Using)) + " " + stmt.Module)
+	if stmt.Rparen >= 0 {
+		result.WriteString("(")
+		for i, arg := range stmt.Args {
+			if i > 0 {
+				result.WriteString(",")
+			}
+			result.WriteString(p.newline(level+1, arg.Pos) + arg.Text)
+		}
+		if len(stmt.Args) > 0 {
+			result.WriteString(p.newline(level, stmt.Rparen))
+		}
+		result.WriteString(")")
+	}
+	return result.String()
+}
+
+// formatPragmaStatement 格式化PRAGMA语句
+func (p *printer) formatPragmaStatement(stmt *ast.PragmaStmt) string {
+	result := p.keyword(string(stmt.Pragma)) + " " + stmt.Name.String()
+	switch {
+	case stmt.Value == nil:
+	case stmt.Eq:
+		result += " = " + stmt.Value.Text
+	default:
+		result += "(" + stmt.Value.Text + ")"
+	}
+	return result
+}
+
+// formatAttachStatement 格式化ATTACH DATABASE语句
+func (p *printer) formatAttachStatement(stmt *ast.AttachStmt, level int) string {
+	return p.keyword(string(stmt.Attach)) + " " + p.formatExpr(stmt.File, level) + " " +
+		p.keyword(string(stmt.As)) + " " + stmt.Schema
+}
+
+// formatColumnDefs 格式化列定义，AlignColumns为true时对齐列名、类型和约束
+func (p *printer) formatColumnDefs(columns []*ast.ColumnDef) []string {
+	nameWidth, typeWidth := 0, 0
+	if p.AlignColumns {
+		for _, column := range columns {
+			nameWidth = max(nameWidth, utf8.RuneCountInString(column.Name.String()))
+			if column.Type != nil {
+				typeWidth = max(typeWidth, utf8.RuneCountInString(column.Type.Text))
+			}
+		}
+	}
+
+	lines := make([]string, len(columns))
+	for i, column := range columns {
+		parts := []string{pad(column.Name.String(), nameWidth)}
+		if column.Type != nil {
+			parts = append(parts, pad(column.Type.Text, typeWidth))
+		} else if typeWidth > 0 {
+			parts = append(parts, pad("", typeWidth))
+		}
+		if column.Constraints != "" {
+			parts = append(parts, column.Constraints)
+		}
+		lines[i] = strings.TrimRight(strings.Join(parts, " "), " ")
+	}
+	return lines
+}
+
+// pad 在s右侧补空格至width个字符
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
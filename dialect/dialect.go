@@ -0,0 +1,123 @@
+// Package dialect describes the differences between SQL dialects and
+// keeps a registry of the known ones.
+package dialect
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/lexer"
+	"github.com/BruceDu521/sql-formatter/parser"
+)
+
+// Dialect describes the lexical and syntactic rules of a SQL dialect.
+// Implementations usually embed Generic and override what differs.
+type Dialect interface {
+	// Name returns the name the dialect is registered under
+	Name() string
+	// Keywords returns words tokenized as keywords in addition to the common ones
+	Keywords() []string
+	// Reserved returns keywords that cannot be bare identifiers or implicit
+	// aliases in addition to the common ones
+	Reserved() []string
+	// IdentifierQuotes returns the characters that open a quoted identifier
+	IdentifierQuotes() string
+	// Strings returns the string literal rules
+	Strings() lexer.StringRules
+	// LineComments returns the prefixes of comments running to the end of the line
+	LineComments() []string
+	// Placeholders returns the characters that start a bind parameter
+	Placeholders() string
+	// Operators returns multi-character operators in addition to the common
+	// ones, longest first
+	Operators() []string
+	// Scripts returns the rules for statement terminators in scripts
+	Scripts() lexer.ScriptRules
+	// Statements returns parsers for statements the common grammar does not
+	// know, keyed by their upper-case first word
+	Statements() map[string]parser.StatementParser
+}
+
+// Generic is the dialect-neutral default: double-quoted and backtick
+// identifiers, -- comments, dollar-quoted strings and ?, $1 and :name
+// placeholders
+type Generic struct{}
+
+// Name implements Dialect
+func (Generic) Name() string { return "generic" }
+
+// Keywords implements Dialect
+func (Generic) Keywords() []string { return nil }
+
+// Reserved implements Dialect
+func (Generic) Reserved() []string { return nil }
+
+// IdentifierQuotes implements Dialect
+func (Generic) IdentifierQuotes() string { return lexer.DefaultConfig().IdentifierQuotes }
+
+// Strings implements Dialect
+func (Generic) Strings() lexer.StringRules { return lexer.DefaultConfig().Strings }
+
+// LineComments implements Dialect
+func (Generic) LineComments() []string { return lexer.DefaultConfig().LineComments }
+
+// Placeholders implements Dialect
+func (Generic) Placeholders() string { return lexer.DefaultConfig().Placeholders }
+
+// Operators implements Dialect
+func (Generic) Operators() []string { return nil }
+
+// Scripts implements Dialect
+func (Generic) Scripts() lexer.ScriptRules { return lexer.ScriptRules{} }
+
+// Statements implements Dialect
+func (Generic) Statements() map[string]parser.StatementParser { return nil }
+
+// LexerConfig returns the tokenizer rules of d
+func LexerConfig(d Dialect) *lexer.Config {
+	return &lexer.Config{
+		Keywords:         d.Keywords(),
+		IdentifierQuotes: d.IdentifierQuotes(),
+		Strings:          d.Strings(),
+		LineComments:     d.LineComments(),
+		Placeholders:     d.Placeholders(),
+		Operators:        d.Operators(),
+		Scripts:          d.Scripts(),
+	}
+}
+
+// ParserConfig returns the grammar rules of d
+func ParserConfig(d Dialect) *parser.Config {
+	return &parser.Config{
+		Reserved:   d.Reserved(),
+		Statements: d.Statements(),
+	}
+}
+
+// registry 已注册的方言，键为小写名称
+var registry = map[string]Dialect{}
+
+func init() {
+	Register(Generic{})
+}
+
+// Register makes a dialect available to Lookup under its name
+func Register(d Dialect) {
+	registry[strings.ToLower(d.Name())] = d
+}
+
+// Lookup returns the dialect registered under name (case-insensitive)
+func Lookup(name string) (Dialect, bool) {
+	d, ok := registry[strings.ToLower(name)]
+	return d, ok
+}
+
+// Names returns the names of all registered dialects in sorted order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,47 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+func TestLookup(t *testing.T) {
+	d, ok := Lookup("Generic")
+	if !ok || d.Name() != "generic" {
+		t.Fatalf("Expected generic dialect, got %v %v", d, ok)
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Errorf("Expected unknown dialect lookup to fail")
+	}
+
+	cfg := LexerConfig(d)
+	if cfg.IdentifierQuotes != "\"`" || !cfg.Strings.DollarQuotes || len(cfg.LineComments) != 1 {
+		t.Errorf("Unexpected generic lexer config %+v", cfg)
+	}
+}
+
+func TestPostgresLexerConfig(t *testing.T) {
+	tokens, err := lexer.TokenizeWithConfig("a ?| b ? c $1 E'\\''", LexerConfig(Postgres{}))
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	var values []string
+	for _, tok := range tokens {
+		if tok.Type != lexer.Whitespace {
+			values = append(values, tok.Type.String()+" "+tok.Value)
+		}
+	}
+	expected := []string{"Identifier a", "Operator ?|", "Identifier b", "Operator ?", "Identifier c", "Placeholder $1", "String E'\\''"}
+	if strings.Join(values, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func TestNames(t *testing.T) {
+	expected := "generic mysql oracle postgres sqlite tsql"
+	if names := strings.Join(Names(), " "); names != expected {
+		t.Errorf("Expected dialects %q, got %q", expected, names)
+	}
+}
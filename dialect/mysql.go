@@ -0,0 +1,43 @@
+package dialect
+
+import "github.com/BruceDu521/sql-formatter/lexer"
+
+// MySQL is the MySQL and MariaDB dialect: backtick identifiers, # comments,
+// double-quoted strings with backslash escapes, index hints, ? parameters
+// and DELIMITER directives in scripts
+type MySQL struct {
+	Generic
+}
+
+func init() {
+	Register(MySQL{})
+}
+
+// Name implements Dialect
+func (MySQL) Name() string { return "mysql" }
+
+// Keywords implements Dialect
+func (MySQL) Keywords() []string { return []string{"FOR", "FORCE", "USE"} }
+
+// Reserved implements Dialect
+func (MySQL) Reserved() []string { return []string{"FORCE", "USE"} }
+
+// IdentifierQuotes implements Dialect
+func (MySQL) IdentifierQuotes() string { return "`" }
+
+// Strings implements Dialect
+func (MySQL) Strings() lexer.StringRules {
+	return lexer.StringRules{Prefixes: []string{"N", "B", "X"}, BackslashEscapes: true, DoubleQuotes: true}
+}
+
+// LineComments implements Dialect
+func (MySQL) LineComments() []string { return []string{"--", "#"} }
+
+// Placeholders implements Dialect
+func (MySQL) Placeholders() string { return "?" }
+
+// Operators implements Dialect
+func (MySQL) Operators() []string { return []string{":="} }
+
+// Scripts implements Dialect
+func (MySQL) Scripts() lexer.ScriptRules { return lexer.ScriptRules{DelimiterDirective: true} }
@@ -0,0 +1,56 @@
+package dialect
+
+import (
+	"github.com/BruceDu521/sql-formatter/lexer"
+	"github.com/BruceDu521/sql-formatter/parser"
+)
+
+// Oracle is the Oracle dialect: q'[...]' strings, :name binds, CONNECT BY
+// hierarchical queries, (+) outer join markers, MINUS, PL/SQL anonymous
+// blocks, stored procedure and package bodies that span semicolons, and /
+// terminator lines in SQL*Plus scripts
+type Oracle struct {
+	Generic
+}
+
+func init() {
+	Register(Oracle{})
+}
+
+// Name implements Dialect
+func (Oracle) Name() string { return "oracle" }
+
+// Keywords implements Dialect
+func (Oracle) Keywords() []string {
+	return []string{"BEGIN", "CONNECT", "DECLARE", "ELSIF", "EXCEPTION", "FOR", "LOOP", "MINUS", "NOCYCLE", "PRIOR", "SIBLINGS", "START", "WHILE"}
+}
+
+// Reserved implements Dialect
+func (Oracle) Reserved() []string { return []string{"CONNECT", "MINUS", "PRIOR", "START"} }
+
+// IdentifierQuotes implements Dialect
+func (Oracle) IdentifierQuotes() string { return `"` }
+
+// Strings implements Dialect
+func (Oracle) Strings() lexer.StringRules {
+	return lexer.StringRules{Prefixes: []string{"N"}, QQuotes: true}
+}
+
+// Placeholders implements Dialect
+func (Oracle) Placeholders() string { return ":" }
+
+// Operators implements Dialect
+func (Oracle) Operators() []string { return []string{":="} }
+
+// Scripts implements Dialect
+func (Oracle) Scripts() lexer.ScriptRules {
+	return lexer.ScriptRules{BatchSeparators: []string{"/"}, Blocks: true, Routines: true}
+}
+
+// Statements implements Dialect
+func (Oracle) Statements() map[string]parser.StatementParser {
+	return map[string]parser.StatementParser{
+		"DECLARE": (*parser.Parser).ParseBlock,
+		"BEGIN":   (*parser.Parser).ParseBlock,
+	}
+}
@@ -0,0 +1,39 @@
+package dialect
+
+import "github.com/BruceDu521/sql-formatter/lexer"
+
+// Postgres is the PostgreSQL dialect: double-quoted identifiers, E'...'
+// escape strings, dollar-quoted bodies, $1 parameters, ARRAY[...],
+// ILIKE and the JSON and containment operators
+type Postgres struct {
+	Generic
+}
+
+func init() {
+	Register(Postgres{})
+}
+
+// Name implements Dialect
+func (Postgres) Name() string { return "postgres" }
+
+// Keywords implements Dialect
+func (Postgres) Keywords() []string { return []string{"ARRAY", "ILIKE"} }
+
+// Reserved implements Dialect
+func (Postgres) Reserved() []string { return []string{"ARRAY", "ILIKE"} }
+
+// IdentifierQuotes implements Dialect
+func (Postgres) IdentifierQuotes() string { return `"` }
+
+// Strings implements Dialect
+func (Postgres) Strings() lexer.StringRules {
+	return lexer.StringRules{Prefixes: []string{"U&", "E", "B", "X"}, DollarQuotes: true}
+}
+
+// Placeholders implements Dialect; ? is the JSON key-exists operator
+func (Postgres) Placeholders() string { return "$:" }
+
+// Operators implements Dialect
+func (Postgres) Operators() []string {
+	return []string{"!~~*", "~~*", "!~~", "!~*", "?|", "?&", "@?", "@@", "#-", "~*", "!~", "~~"}
+}
@@ -0,0 +1,45 @@
+package dialect
+
+import (
+	"github.com/BruceDu521/sql-formatter/lexer"
+	"github.com/BruceDu521/sql-formatter/parser"
+)
+
+// SQLite is the SQLite dialect: PRAGMA, ATTACH and DETACH statements,
+// INSERT OR REPLACE and the other conflict clauses, virtual tables and
+// ?NNN, :name, @name and $name parameters
+type SQLite struct {
+	Generic
+}
+
+func init() {
+	Register(SQLite{})
+}
+
+// Name implements Dialect
+func (SQLite) Name() string { return "sqlite" }
+
+// Keywords implements Dialect
+func (SQLite) Keywords() []string {
+	return []string{"ABORT", "ATTACH", "DATABASE", "DETACH", "FAIL", "PRAGMA", "ROLLBACK", "VIRTUAL"}
+}
+
+// IdentifierQuotes implements Dialect
+func (SQLite) IdentifierQuotes() string { return "\"`[" }
+
+// Strings implements Dialect
+func (SQLite) Strings() lexer.StringRules {
+	return lexer.StringRules{Prefixes: []string{"X"}}
+}
+
+// Placeholders implements Dialect
+func (SQLite) Placeholders() string { return "?:@$" }
+
+// Statements implements Dialect
+func (SQLite) Statements() map[string]parser.StatementParser {
+	return map[string]parser.StatementParser{
+		"PRAGMA": (*parser.Parser).ParsePragma,
+		"ATTACH": (*parser.Parser).ParseAttach,
+		"DETACH": (*parser.Parser).ParseDetach,
+	}
+}
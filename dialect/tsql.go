@@ -0,0 +1,60 @@
+package dialect
+
+import (
+	"github.com/BruceDu521/sql-formatter/lexer"
+	"github.com/BruceDu521/sql-formatter/parser"
+)
+
+// TSQL is the SQL Server dialect: [bracketed] identifiers, N'...' strings,
+// @variables, SELECT TOP, CROSS/OUTER APPLY, DECLARE and SET, and GO batch
+// separators; procedure, function and trigger bodies are not split at their
+// semicolons
+type TSQL struct {
+	Generic
+}
+
+func init() {
+	Register(TSQL{})
+}
+
+// Name implements Dialect
+func (TSQL) Name() string { return "tsql" }
+
+// Keywords implements Dialect
+func (TSQL) Keywords() []string {
+	return []string{"APPLY", "DECLARE", "PERCENT", "TOP"}
+}
+
+// Reserved implements Dialect
+func (TSQL) Reserved() []string {
+	return []string{"APPLY", "DECLARE", "PERCENT", "TOP"}
+}
+
+// IdentifierQuotes implements Dialect
+func (TSQL) IdentifierQuotes() string { return "\"[" }
+
+// Strings implements Dialect
+func (TSQL) Strings() lexer.StringRules {
+	return lexer.StringRules{Prefixes: []string{"N"}}
+}
+
+// Placeholders implements Dialect; $ covers $action in OUTPUT clauses
+func (TSQL) Placeholders() string { return "@$" }
+
+// Operators implements Dialect
+func (TSQL) Operators() []string {
+	return []string{"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^="}
+}
+
+// Scripts implements Dialect
+func (TSQL) Scripts() lexer.ScriptRules {
+	return lexer.ScriptRules{BatchSeparators: []string{"GO"}, RepeatCount: true, Routines: true}
+}
+
+// Statements implements Dialect
+func (TSQL) Statements() map[string]parser.StatementParser {
+	return map[string]parser.StatementParser{
+		"DECLARE": (*parser.Parser).ParseDeclare,
+		"SET":     (*parser.Parser).ParseSet,
+	}
+}
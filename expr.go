@@ -0,0 +1,266 @@
+package sqlformatter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// formatExpr 格式化表达式，level为表达式所在行的缩进级别；子查询在括号内换行并缩进一级
+func (p *printer) formatExpr(expr ast.Expr, level int) string {
+	switch e := expr.(type) {
+	case *ast.Name:
+		return e.String()
+	case *ast.Literal:
+		return e.Value
+	case *ast.Placeholder:
+		return e.Value
+	case *ast.RawExpr:
+		return e.Text
+	case *ast.BinaryExpr:
+		return p.formatExpr(e.Left, level) + " " + e.Op + " " + p.formatExpr(e.Right, level)
+	case *ast.UnaryExpr:
+		operand := p.formatExpr(e.Expr, level)
+		if isWordOperator(e.Op) || startsWithOperator(operand) {
+			return e.Op + " " + operand
+		}
+		return e.Op + operand
+	case *ast.FuncCall:
+		args := p.formatExprList(e.Args, level)
+		if e.Distinct != "" {
+			args = string(e.Distinct) + " " + args
+		}
+		return e.Name.String() + "(" + args + ")" + p.formatOver(e.Over, level)
+	case *ast.ParenExpr:
+		return "(" + p.formatExpr(e.Expr, level) + ")"
+	case *ast.TupleExpr:
+		return "(" + p.formatExprList(e.Exprs, level) + ")"
+	case *ast.SubqueryExpr:
+		return p.formatSubquery(e, level)
+	case *ast.ExistsExpr:
+		return string(e.Exists) + " " + p.formatExpr(e.Subquery, level)
+	case *ast.InExpr:
+		if e.Subquery != nil {
+			return p.formatExpr(e.Expr, level) + " " + string(e.Op) + " " + p.formatExpr(e.Subquery, level)
+		}
+		return p.formatExpr(e.Expr, level) + " " + string(e.Op) + " (" + p.formatExprList(e.List, level) + ")"
+	case *ast.BetweenExpr:
+		return p.formatExpr(e.Expr, level) + " " + string(e.Op) + " " + p.formatExpr(e.Low, level) +
+			" " + string(e.And) + " " + p.formatExpr(e.High, level)
+	case *ast.CaseExpr:
+		return p.formatCaseExpr(e, level)
+	case *ast.CastExpr:
+		if e.Cast == "" {
+			return p.formatExpr(e.Expr, level) + string(e.As) + e.Type.Text
+		}
+		return string(e.Cast) + "(" + p.formatExpr(e.Expr, level) + " " + string(e.As) + " " + e.Type.Text + ")"
+	case *ast.OuterJoinExpr:
+		return p.formatExpr(e.Expr, level) + "(+)"
+	case *ast.SubscriptExpr:
+		return p.formatExpr(e.Expr, level) + "[" + e.Index + "]"
+	case *ast.ArrayExpr:
+		return p.keyword(string(e.Array)) + "[" + p.formatExprList(e.Elems, level) + "]"
+	}
+	return ""
+}
+
+// formatExprList 格式化逗号分隔的表达式列表
+func (p *printer) formatExprList(exprs []ast.Expr, level int) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = p.formatExpr(expr, level)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCaseExpr 格式化CASE表达式，每个WHEN和ELSE各占一行并缩进一级，END与所在行对齐；
+// 设置了CaseInlineWidth时，单行不超过该宽度的CASE保持在一行
+func (p *printer) formatCaseExpr(e *ast.CaseExpr, level int) string {
+	if p.CaseInlineWidth > 0 {
+		comments := p.comments
+		inline := p.formatInlineCase(e, level)
+		if !strings.Contains(inline, "\n") && utf8.RuneCountInString(inline) <= p.CaseInlineWidth {
+			return inline
+		}
+		// 单行放不下时按块输出，恢复尝试中输出的注释
+		p.comments = comments
+	}
+
+	var result strings.Builder
+	result.WriteString(p.keyword("CASE"))
+	if e.Operand != nil {
+		result.WriteString(" " + p.formatExpr(e.Operand, level))
+	}
+	for _, when := range e.Whens {
+		result.WriteString(p.newline(level+1, ast.Pos(when)))
+		result.WriteString(p.keyword("WHEN") + " " + p.formatExpr(when.Cond, level+1))
+		result.WriteString(" " + p.keyword("THEN") + " " + p.formatExpr(when.Result, level+1))
+	}
+	if e.Else != nil {
+		result.WriteString(p.newline(level+1, ast.Pos(e.Else)))
+		result.WriteString(p.keyword("ELSE") + " " + p.formatExpr(e.Else, level+1))
+	}
+	result.WriteString(p.newline(level, e.End) + p.keyword("END"))
+	return result.String()
+}
+
+// formatInlineCase 将CASE表达式格式化为单行文本
+func (p *printer) formatInlineCase(e *ast.CaseExpr, level int) string {
+	var result strings.Builder
+	result.WriteString(p.keyword("CASE"))
+	if e.Operand != nil {
+		result.WriteString(" " + p.formatExpr(e.Operand, level))
+	}
+	for _, when := range e.Whens {
+		result.WriteString(" " + p.keyword("WHEN") + " " + p.formatExpr(when.Cond, level))
+		result.WriteString(" " + p.keyword("THEN") + " " + p.formatExpr(when.Result, level))
+	}
+	if e.Else != nil {
+		result.WriteString(" " + p.keyword("ELSE") + " " + p.formatExpr(e.Else, level))
+	}
+	result.WriteString(" " + p.keyword("END"))
+	return result.String()
+}
+
+// formatCondition 格式化布尔条件，每个AND/OR连接的操作数各占一行，
+// 含AND/OR的括号分组在括号内缩进一级
+func (p *printer) formatCondition(expr ast.Expr, level int) string {
+	operands, operators := splitLogical(expr)
+
+	var result strings.Builder
+	result.WriteString(p.formatConditionOperand(operands[0], level))
+	for i, op := range operators {
+		operand := operands[i+1]
+		op = p.keyword(op)
+		if p.LogicalOperators == OperatorTrailing {
+			result.WriteString(" " + op + p.newline(level, ast.Pos(operand)))
+		} else {
+			result.WriteString(p.newline(level, ast.Pos(operand)) + op + " ")
+		}
+		result.WriteString(p.formatConditionOperand(operand, level))
+	}
+	return result.String()
+}
+
+// formatConditionOperand 格式化AND/OR的操作数，展开其中的括号分组
+func (p *printer) formatConditionOperand(expr ast.Expr, level int) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		if isLogical(e.Expr) {
+			return "(" + p.newline(level+1, ast.Pos(e.Expr)) + p.formatCondition(e.Expr, level+1) +
+				p.newline(level, e.Rparen) + ")"
+		}
+	case *ast.UnaryExpr:
+		if _, ok := e.Expr.(*ast.ParenExpr); ok && isWordOperator(e.Op) {
+			return e.Op + " " + p.formatConditionOperand(e.Expr, level)
+		}
+	}
+	return p.formatExpr(expr, level)
+}
+
+// formatOver 格式化窗口函数的OVER子句
+func (p *printer) formatOver(over *ast.OverClause, level int) string {
+	switch {
+	case over == nil:
+		return ""
+	case over.Spec == nil:
+		return " " + p.keyword(string(over.Over)) + " " + over.Name.String()
+	}
+	return " " + p.keyword(string(over.Over)) + " " + p.formatWindowSpec(over.Spec, level)
+}
+
+// formatWindowSpec 格式化括号中的窗口定义；设置了WindowInlineWidth时，不超过该宽度的定义保持在一行，
+// 否则各组成部分在括号内各占一行并缩进一级
+func (p *printer) formatWindowSpec(spec *ast.WindowSpec, level int) string {
+	if p.WindowInlineWidth > 0 {
+		comments := p.comments
+		inline := "(" + strings.TrimPrefix(p.formatWindowParts(spec, level, func(int) string { return " " }), " ") + ")"
+		if !strings.Contains(inline, "\n") && utf8.RuneCountInString(inline) <= p.WindowInlineWidth {
+			return inline
+		}
+		p.comments = comments
+	}
+
+	parts := p.formatWindowParts(spec, level+1, func(pos int) string { return p.newline(level+1, pos) })
+	if parts == "" {
+		return "()"
+	}
+	return "(" + parts + p.newline(level, spec.Rparen) + ")"
+}
+
+// formatWindowParts 格式化窗口定义的各组成部分，sep返回位于pos的部分之前的分隔
+func (p *printer) formatWindowParts(spec *ast.WindowSpec, level int, sep func(pos int) string) string {
+	var result strings.Builder
+	if spec.Name != nil {
+		result.WriteString(sep(ast.Pos(spec.Name)) + spec.Name.String())
+	}
+	if len(spec.PartitionBy) > 0 {
+		result.WriteString(sep(ast.Pos(spec.PartitionBy[0])) + p.keyword("PARTITION BY") + " ")
+		result.WriteString(p.formatExprList(spec.PartitionBy, level))
+	}
+	if len(spec.OrderBy) > 0 {
+		result.WriteString(sep(ast.Pos(spec.OrderBy[0])) + p.keyword("ORDER BY") + " ")
+		result.WriteString(p.formatOrderBy(spec.OrderBy, level))
+	}
+	if frame := spec.Frame; frame != nil {
+		result.WriteString(sep(frame.Pos) + p.keyword(string(frame.Units)) + " ")
+		if frame.Between != "" {
+			result.WriteString(p.keyword(string(frame.Between)) + " ")
+		}
+		result.WriteString(p.formatFrameBound(frame.Start, level))
+		if frame.End != nil {
+			result.WriteString(" " + p.keyword(string(frame.And)) + " " + p.formatFrameBound(frame.End, level))
+		}
+	}
+	return result.String()
+}
+
+// formatFrameBound 格式化窗口边界
+func (p *printer) formatFrameBound(bound *ast.FrameBound, level int) string {
+	if bound.Offset == nil {
+		return p.keyword(string(bound.Bound))
+	}
+	return p.formatExpr(bound.Offset, level) + " " + p.keyword(string(bound.Bound))
+}
+
+// formatSubquery 格式化括号中的子查询，查询体缩进一级，右括号与所在行对齐
+func (p *printer) formatSubquery(e *ast.SubqueryExpr, level int) string {
+	return "(" + p.newline(level+1, ast.Pos(e.Select)) + p.formatSQL(e.Select, level+1) +
+		p.newline(level, e.Rparen) + ")"
+}
+
+// splitLogical 按原文顺序展开AND/OR连接的表达式，返回操作数及其间的运算符
+func splitLogical(expr ast.Expr) ([]ast.Expr, []string) {
+	e, ok := expr.(*ast.BinaryExpr)
+	if !ok || !isLogical(e) {
+		return []ast.Expr{expr}, nil
+	}
+	operands, operators := splitLogical(e.Left)
+	right, rightOperators := splitLogical(e.Right)
+	operators = append(append(operators, e.Op), rightOperators...)
+	return append(operands, right...), operators
+}
+
+// isLogical 表达式是否为AND/OR运算
+func isLogical(expr ast.Expr) bool {
+	e, ok := expr.(*ast.BinaryExpr)
+	return ok && (strings.EqualFold(e.Op, "AND") || strings.EqualFold(e.Op, "OR"))
+}
+
+// isWordOperator 运算符是否由字母组成（如NOT），需要与操作数以空格分隔
+func isWordOperator(op string) bool {
+	for _, r := range op {
+		if !unicode.IsLetter(r) && r != ' ' {
+			return false
+		}
+	}
+	return op != ""
+}
+
+// startsWithOperator 判断文本是否以运算符字符开头；前缀运算符直接拼接这样的操作数
+// 会组成另一个运算符或注释（如 - -a 变成 --a），需要用空格隔开
+func startsWithOperator(text string) bool {
+	return text != "" && strings.ContainsRune("+-*/<>=~!@#%^&|", rune(text[0]))
+}
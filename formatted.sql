@@ -6,7 +6,8 @@ FROM
   users u
   JOIN products p on u.id = p.user_id
 WHERE
-  u.age > 25 and p.category = 'electronics'
+  u.age > 25
+  AND p.category = 'electronics'
 GROUP BY
   u.id
 ORDER BY
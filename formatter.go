@@ -2,432 +2,261 @@ package sqlformatter
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/dialect"
+	"github.com/BruceDu521/sql-formatter/lexer"
+	"github.com/BruceDu521/sql-formatter/parser"
+)
+
+// SemicolonPolicy controls the semicolon after the last statement
+type SemicolonPolicy int
+
+const (
+	// SemicolonPreserve keeps the semicolon after the last statement only if the input had one
+	SemicolonPreserve SemicolonPolicy = iota
+	// SemicolonAlways terminates the last statement with a semicolon
+	SemicolonAlways
+	// SemicolonNever drops the semicolon after the last statement, except
+	// the one after the END of a procedural block, which is part of its syntax
+	SemicolonNever
+)
+
+// OperatorPlacement controls where AND/OR go when a condition is split across lines
+type OperatorPlacement int
+
+const (
+	// OperatorLeading starts each continued line with the operator
+	OperatorLeading OperatorPlacement = iota
+	// OperatorTrailing ends each broken line with the operator
+	OperatorTrailing
 )
 
 // Formatter SQL formatter configuration
 type Formatter struct {
 	IndentSize   int
 	KeywordUpper bool
+	BlankLines   int             // blank lines between statements
+	Semicolon    SemicolonPolicy // semicolon after the last statement
+
+	// LogicalOperators places AND/OR at the start or end of the lines of
+	// a WHERE/HAVING condition
+	LogicalOperators OperatorPlacement
+
+	// JoinConditionNewline puts ON/USING on its own line below the joined table
+	JoinConditionNewline bool
+
+	// SplitJoinConditions breaks ON conditions on AND/OR like WHERE conditions
+	SplitJoinConditions bool
+
+	// CaseInlineWidth keeps CASE expressions no longer than this many
+	// characters on one line; 0 always lays them out as blocks
+	CaseInlineWidth int
+
+	// WindowInlineWidth keeps window specifications no longer than this many
+	// characters on one line; 0 always breaks them inside the parentheses
+	WindowInlineWidth int
+
+	// AlignColumns pads the column names and types of CREATE TABLE so that
+	// types and column constraints start in the same column
+	AlignColumns bool
+
+	// Dialect selects the lexical and syntax rules of the input; nil uses
+	// the generic dialect
+	Dialect dialect.Dialect
 }
 
 // NewFormatter creates a new formatter instance
 func NewFormatter() *Formatter {
 	return &Formatter{
-		IndentSize:   2,
-		KeywordUpper: true,
+		IndentSize:        2,
+		KeywordUpper:      true,
+		BlankLines:        1,
+		Semicolon:         SemicolonPreserve,
+		LogicalOperators:  OperatorLeading,
+		WindowInlineWidth: 60,
 	}
 }
 
-// Format formats the given SQL statement
+// Format formats the given SQL statement or script; statements separated by
+// semicolons are formatted independently
 func (f *Formatter) Format(sql string) (string, error) {
 	if strings.TrimSpace(sql) == "" {
 		return "", fmt.Errorf("SQL statement cannot be empty")
 	}
 
-	// 清理并标准化SQL
-	cleaned := f.cleanSQL(sql)
-
-	// 格式化SQL
-	formatted := f.formatSQL(cleaned)
-
-	return formatted, nil
-}
-
-// cleanSQL 清理SQL语句
-func (f *Formatter) cleanSQL(sql string) string {
-	// 移除多余的空白字符
-	sql = regexp.MustCompile(`\s+`).ReplaceAllString(sql, " ")
-	// 移除首尾空白
-	sql = strings.TrimSpace(sql)
-	return sql
-}
-
-// formatSQL 格式化SQL语句
-func (f *Formatter) formatSQL(sql string) string {
-	// SQL关键字模式
-	keywords := []string{
-		"SELECT", "FROM", "WHERE", "GROUP BY", "HAVING", "ORDER BY", "LIMIT",
-		"INSERT", "INTO", "VALUES", "UPDATE", "SET", "DELETE",
-		"JOIN", "INNER JOIN", "LEFT JOIN", "RIGHT JOIN", "FULL JOIN",
-		"UNION", "UNION ALL", "CASE", "WHEN", "THEN", "ELSE", "END",
+	// 词法分析
+	cfg := dialect.LexerConfig(f.dialect())
+	tokens, err := lexer.TokenizeWithConfig(sql, cfg)
+	if err != nil {
+		return "", err
 	}
 
-	// 对关键字进行处理
-	for _, keyword := range keywords {
-		pattern := `(?i)\b` + regexp.QuoteMeta(keyword) + `\b`
-		re := regexp.MustCompile(pattern)
-		replacement := f.keyword(keyword)
-		sql = re.ReplaceAllString(sql, replacement)
+	// 按分号拆分语句，找到最后一条非空语句
+	stmts := lexer.SplitWithConfig(tokens, cfg)
+	last := -1
+	for i, stmt := range stmts {
+		if !stmt.IsEmpty() {
+			last = i
+		}
 	}
 
-	// 检测SQL类型并格式化
-	sqlUpper := strings.ToUpper(strings.TrimSpace(sql))
-	if strings.HasPrefix(sqlUpper, "SELECT") {
-		return f.formatSelectStatement(sql)
-	} else if strings.HasPrefix(sqlUpper, "INSERT") {
-		return f.formatInsertStatement(sql)
-	} else if strings.HasPrefix(sqlUpper, "UPDATE") {
-		return f.formatUpdateStatement(sql)
-	} else if strings.HasPrefix(sqlUpper, "DELETE") {
-		return f.formatDeleteStatement(sql)
+	var formatted []string
+	for i, stmt := range stmts {
+		// 由批分隔符结束的语句保持原样，不补结束符
+		semicolon := !stmt.IsEmpty() && (i < last || f.lastSemicolon(stmt)) &&
+			(stmt.Separator == "" || stmt.Terminated)
+		if text := f.formatStatement(stmt, semicolon); text != "" {
+			formatted = append(formatted, text)
+		}
 	}
 
-	return sql
+	return strings.Join(formatted, "\n"+strings.Repeat("\n", f.BlankLines)), nil
 }
 
-// formatSelectStatement 格式化SELECT语句
-func (f *Formatter) formatSelectStatement(sql string) string {
-	// 使用正则表达式分割SQL的各个部分
-	parts := f.splitSelectSQL(sql)
-
-	var result strings.Builder
-	indent := f.getIndent(1)
-
-	// SELECT部分
-	if selectPart := parts["SELECT"]; selectPart != "" {
-		result.WriteString(f.keyword("SELECT"))
-		result.WriteString("\n")
-		selectColumns := f.formatSelectColumns(selectPart)
-		result.WriteString(indent + selectColumns)
-	}
-
-	// FROM部分
-	if fromPart := parts["FROM"]; fromPart != "" {
-		result.WriteString("\n" + f.keyword("FROM"))
-		result.WriteString("\n")
-		fromClause := f.formatFromClause(fromPart)
-		result.WriteString(indent + fromClause)
-	}
-
-	// WHERE部分
-	if wherePart := parts["WHERE"]; wherePart != "" {
-		result.WriteString("\n" + f.keyword("WHERE"))
-		result.WriteString("\n")
-		result.WriteString(indent + wherePart)
-	}
-
-	// GROUP BY部分
-	if groupByPart := parts["GROUP BY"]; groupByPart != "" {
-		result.WriteString("\n" + f.keyword("GROUP BY"))
-		result.WriteString("\n")
-		result.WriteString(indent + groupByPart)
-	}
-
-	// HAVING部分
-	if havingPart := parts["HAVING"]; havingPart != "" {
-		result.WriteString("\n" + f.keyword("HAVING"))
-		result.WriteString("\n")
-		result.WriteString(indent + havingPart)
-	}
-
-	// ORDER BY部分
-	if orderByPart := parts["ORDER BY"]; orderByPart != "" {
-		result.WriteString("\n" + f.keyword("ORDER BY"))
-		result.WriteString("\n")
-		result.WriteString(indent + orderByPart)
+// dialect 返回当前使用的方言
+func (f *Formatter) dialect() dialect.Dialect {
+	if f.Dialect == nil {
+		return dialect.Generic{}
 	}
+	return f.Dialect
+}
 
-	// LIMIT部分
-	if limitPart := parts["LIMIT"]; limitPart != "" {
-		result.WriteString("\n" + f.keyword("LIMIT"))
-		result.WriteString("\n")
-		result.WriteString(indent + limitPart)
+// lastSemicolon 最后一条语句之后是否输出分号；过程块END之后的分号属于语法，不能省略
+func (f *Formatter) lastSemicolon(stmt lexer.Statement) bool {
+	switch f.Semicolon {
+	case SemicolonAlways:
+		return true
+	case SemicolonNever:
+		return stmt.Block && stmt.Terminated
 	}
-
-	return result.String()
+	return stmt.Terminated
 }
 
-// splitSelectSQL 分割SELECT SQL的各个部分
-func (f *Formatter) splitSelectSQL(sql string) map[string]string {
-	parts := make(map[string]string)
-
-	// 定义关键字的正则表达式模式
-	patterns := map[string]string{
-		"SELECT":   `(?i)\bSELECT\s+(.*?)(?:\s+FROM|\s*$)`,
-		"FROM":     `(?i)\bFROM\s+(.*?)(?:\s+WHERE|\s+GROUP\s+BY|\s+HAVING|\s+ORDER\s+BY|\s+LIMIT|\s*$)`,
-		"WHERE":    `(?i)\bWHERE\s+(.*?)(?:\s+GROUP\s+BY|\s+HAVING|\s+ORDER\s+BY|\s+LIMIT|\s*$)`,
-		"GROUP BY": `(?i)\bGROUP\s+BY\s+(.*?)(?:\s+HAVING|\s+ORDER\s+BY|\s+LIMIT|\s*$)`,
-		"HAVING":   `(?i)\bHAVING\s+(.*?)(?:\s+ORDER\s+BY|\s+LIMIT|\s*$)`,
-		"ORDER BY": `(?i)\bORDER\s+BY\s+(.*?)(?:\s+LIMIT|\s*$)`,
-		"LIMIT":    `(?i)\bLIMIT\s+(.*?)(?:\s*$)`,
+// formatStatement 格式化单条语句，并输出结束符及其后同一行的注释；客户端指令原样输出
+func (f *Formatter) formatStatement(stmt lexer.Statement, semicolon bool) string {
+	if stmt.IsDirective() {
+		return strings.TrimSpace(stmt.Tokens[0].Value)
+	}
+	tokens := trimTokens(stmt.Tokens)
+	result := f.formatTokens(tokens)
+	if semicolon {
+		// 结束符不能跟在行注释之后；打印器可能把语句中间的注释移到末尾
+		if f.endsInLineComment(result) {
+			result += "\n"
+		}
+		result += stmt.Terminator
 	}
-
-	for keyword, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(sql)
-		if len(matches) > 1 {
-			parts[keyword] = strings.TrimSpace(matches[1])
+	for _, tok := range stmt.Trailing {
+		if tok.Type == lexer.Comment {
+			result += " " + tok.Value
 		}
 	}
-
-	return parts
+	result = strings.TrimLeft(result, " ")
+	// 批分隔符独占一行
+	if stmt.Separator != "" {
+		if result != "" {
+			result += "\n"
+		}
+		result += stmt.Separator
+	}
+	return result
 }
 
-// formatSelectColumns 格式化SELECT列
-func (f *Formatter) formatSelectColumns(selectPart string) string {
-	// 分割列名
-	columns := f.splitColumns(selectPart)
-
-	if len(columns) == 0 {
-		return selectPart
+// endsInLineComment 格式化结果的最后一行是否以行注释结尾
+func (f *Formatter) endsInLineComment(result string) bool {
+	tokens, err := lexer.TokenizeWithConfig(result, dialect.LexerConfig(f.dialect()))
+	if err != nil {
+		return false
 	}
-
-	var result strings.Builder
-	for i, col := range columns {
-		if i > 0 {
-			result.WriteString(",\n" + f.getIndent(1))
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].Type != lexer.Whitespace {
+			return isLineComment(tokens[i])
 		}
-		result.WriteString(strings.TrimSpace(col))
 	}
-
-	return result.String()
+	return false
 }
 
-// formatFromClause 格式化FROM子句
-func (f *Formatter) formatFromClause(fromPart string) string {
-	// 处理JOIN
-	joinPattern := `(?i)\b(INNER\s+JOIN|LEFT\s+JOIN|RIGHT\s+JOIN|FULL\s+JOIN|JOIN)\b`
-	re := regexp.MustCompile(joinPattern)
-
-	// 分割JOIN部分
-	parts := re.Split(fromPart, -1)
-	joins := re.FindAllString(fromPart, -1)
-
-	var result strings.Builder
-	result.WriteString(strings.TrimSpace(parts[0])) // 主表
-
-	for i, join := range joins {
-		if i+1 < len(parts) {
-			result.WriteString("\n" + f.getIndent(1))
-			result.WriteString(f.keyword(join) + " " + strings.TrimSpace(parts[i+1]))
-		}
+// formatTokens 格式化一条语句的词法单元，无法解析时退回到词法单元级别的格式化
+func (f *Formatter) formatTokens(tokens []lexer.Token) string {
+	ps := parser.NewWithConfig(tokens, dialect.ParserConfig(f.dialect()))
+	stmts, err := ps.ParseAll()
+	if err != nil || len(stmts) != 1 {
+		return f.formatKeywords(tokens)
 	}
 
-	return result.String()
+	// 注释按原文位置插入，打印器不认识的方言语句同样退回
+	p := newPrinter(f, ps.Comments())
+	stmt := stmts[0]
+	leading := p.leadingComments(ast.Pos(stmt))
+	body := p.formatSQL(stmt, 0)
+	if body == "" {
+		return f.formatKeywords(tokens)
+	}
+	return leading + body + p.restComments()
 }
 
 // splitColumns 分割列名（考虑函数调用中的逗号）
 func (f *Formatter) splitColumns(columnsStr string) []string {
+	tokens, err := lexer.TokenizeWithConfig(columnsStr, dialect.LexerConfig(f.dialect()))
+	if err != nil {
+		return []string{columnsStr}
+	}
+
 	var columns []string
-	var current strings.Builder
-	var parenCount int
-
-	for _, char := range columnsStr {
-		switch char {
-		case '(':
-			parenCount++
-			current.WriteRune(char)
-		case ')':
-			parenCount--
-			current.WriteRune(char)
-		case ',':
-			if parenCount == 0 {
-				columns = append(columns, current.String())
-				current.Reset()
-			} else {
-				current.WriteRune(char)
+	start := 0
+	depth := 0
+	for _, tok := range tokens {
+		switch {
+		case tok.IsPunct(","):
+			if depth == 0 {
+				columns = append(columns, columnsStr[start:tok.Start])
+				start = tok.End
 			}
 		default:
-			current.WriteRune(char)
+			depth += parenDelta(tok)
 		}
 	}
 
-	if current.Len() > 0 {
-		columns = append(columns, current.String())
+	if start < len(columnsStr) {
+		columns = append(columns, columnsStr[start:])
 	}
 
 	return columns
 }
 
-// formatInsertStatement 格式化INSERT语句
-func (f *Formatter) formatInsertStatement(sql string) string {
-	// INSERT INTO table (col1, col2) VALUES (val1, val2)
-	insertPattern := `(?i)\bINSERT\s+INTO\s+(\S+)\s*\(([^)]+)\)\s+VALUES\s*\(([^)]+)\)`
-	re := regexp.MustCompile(insertPattern)
-	matches := re.FindStringSubmatch(sql)
-
-	if len(matches) >= 4 {
-		tableName := strings.TrimSpace(matches[1])
-		columns := strings.TrimSpace(matches[2])
-		values := strings.TrimSpace(matches[3])
-
-		var result strings.Builder
-		indent := f.getIndent(1)
-
-		result.WriteString(f.keyword("INSERT INTO") + " " + tableName)
-		result.WriteString("\n" + indent + "(" + f.formatColumnList(columns) + ")")
-		result.WriteString("\n" + f.keyword("VALUES"))
-		result.WriteString("\n" + indent + "(" + f.formatValueList(values) + ")")
-
-		return result.String()
-	}
-
-	// 如果不匹配标准格式，返回格式化的关键字版本
-	return f.formatKeywords(sql)
+// layoutKeywords 输出时统一大小写的关键字
+var layoutKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "HAVING": true, "LIMIT": true,
+	"INSERT": true, "INTO": true, "VALUES": true, "UPDATE": true, "SET": true, "DELETE": true,
+	"JOIN": true, "UNION": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
 }
 
-// formatUpdateStatement 格式化UPDATE语句
-func (f *Formatter) formatUpdateStatement(sql string) string {
-	// 分割UPDATE语句的各个部分
-	parts := f.splitUpdateSQL(sql)
-
-	var result strings.Builder
-	indent := f.getIndent(1)
-
-	// UPDATE部分
-	if updatePart := parts["UPDATE"]; updatePart != "" {
-		result.WriteString(f.keyword("UPDATE") + " " + updatePart)
-	}
-
-	// SET部分
-	if setPart := parts["SET"]; setPart != "" {
-		result.WriteString("\n" + f.keyword("SET"))
-		result.WriteString("\n" + indent + f.formatSetClause(setPart))
-	}
-
-	// WHERE部分
-	if wherePart := parts["WHERE"]; wherePart != "" {
-		result.WriteString("\n" + f.keyword("WHERE"))
-		result.WriteString("\n" + indent + wherePart)
-	}
-
-	return result.String()
-}
-
-// formatDeleteStatement 格式化DELETE语句
-func (f *Formatter) formatDeleteStatement(sql string) string {
-	// 分割DELETE语句的各个部分
-	parts := f.splitDeleteSQL(sql)
-
-	var result strings.Builder
-	indent := f.getIndent(1)
-
-	// DELETE FROM部分
-	if fromPart := parts["FROM"]; fromPart != "" {
-		result.WriteString(f.keyword("DELETE FROM") + " " + fromPart)
-	}
-
-	// WHERE部分
-	if wherePart := parts["WHERE"]; wherePart != "" {
-		result.WriteString("\n" + f.keyword("WHERE"))
-		result.WriteString("\n" + indent + wherePart)
-	}
-
-	return result.String()
-}
-
-// formatColumnList 格式化列列表
-func (f *Formatter) formatColumnList(columns string) string {
-	cols := f.splitColumns(columns)
-	if len(cols) <= 1 {
-		return columns
-	}
-
-	var result strings.Builder
-	for i, col := range cols {
-		if i > 0 {
-			result.WriteString(", ")
-		}
-		result.WriteString(strings.TrimSpace(col))
-	}
-	return result.String()
-}
-
-// formatValueList 格式化值列表
-func (f *Formatter) formatValueList(values string) string {
-	vals := f.splitColumns(values) // 复用splitColumns逻辑
-	if len(vals) <= 1 {
-		return values
-	}
-
-	var result strings.Builder
-	for i, val := range vals {
-		if i > 0 {
-			result.WriteString(", ")
-		}
-		result.WriteString(strings.TrimSpace(val))
-	}
-	return result.String()
-}
-
-// formatSetClause 格式化SET子句
-func (f *Formatter) formatSetClause(setPart string) string {
-	// 分割SET子句中的赋值语句
-	assignments := f.splitColumns(setPart)
-	if len(assignments) <= 1 {
-		return setPart
-	}
-
+// formatKeywords 格式化关键字（备用方法）
+func (f *Formatter) formatKeywords(tokens []lexer.Token) string {
 	var result strings.Builder
-	indent := f.getIndent(1)
-	for i, assignment := range assignments {
-		if i > 0 {
-			result.WriteString(",\n" + indent)
+	tokens = trimTokens(tokens)
+	for _, tok := range tokens {
+		switch {
+		case tok.Type == lexer.Whitespace:
+			// 保留原文的换行和行首缩进，存储过程等无法解析的语句保持原有的分行；
+			// 行内连续空白合并为一个空格
+			if lines := strings.Count(tok.Value, "\n"); lines > 0 {
+				result.WriteString(strings.Repeat("\n", lines))
+				result.WriteString(tok.Value[strings.LastIndex(tok.Value, "\n")+1:])
+			} else {
+				result.WriteString(" ")
+			}
+		case tok.Type == lexer.Keyword && layoutKeywords[tok.Upper()]:
+			result.WriteString(f.keyword(tok.Value))
+		default:
+			result.WriteString(tok.Value)
 		}
-		result.WriteString(strings.TrimSpace(assignment))
 	}
 	return result.String()
 }
 
-// splitUpdateSQL 分割UPDATE SQL的各个部分
-func (f *Formatter) splitUpdateSQL(sql string) map[string]string {
-	parts := make(map[string]string)
-
-	patterns := map[string]string{
-		"UPDATE": `(?i)\bUPDATE\s+(.*?)(?:\s+SET|\s*$)`,
-		"SET":    `(?i)\bSET\s+(.*?)(?:\s+WHERE|\s*$)`,
-		"WHERE":  `(?i)\bWHERE\s+(.*?)(?:\s*$)`,
-	}
-
-	for keyword, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(sql)
-		if len(matches) > 1 {
-			parts[keyword] = strings.TrimSpace(matches[1])
-		}
-	}
-
-	return parts
-}
-
-// splitDeleteSQL 分割DELETE SQL的各个部分
-func (f *Formatter) splitDeleteSQL(sql string) map[string]string {
-	parts := make(map[string]string)
-
-	patterns := map[string]string{
-		"FROM":  `(?i)\bDELETE\s+FROM\s+(.*?)(?:\s+WHERE|\s*$)`,
-		"WHERE": `(?i)\bWHERE\s+(.*?)(?:\s*$)`,
-	}
-
-	for keyword, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(sql)
-		if len(matches) > 1 {
-			parts[keyword] = strings.TrimSpace(matches[1])
-		}
-	}
-
-	return parts
-}
-
-// formatKeywords 格式化关键字（备用方法）
-func (f *Formatter) formatKeywords(sql string) string {
-	keywords := []string{
-		"INSERT INTO", "VALUES", "UPDATE", "SET", "DELETE FROM", "WHERE",
-	}
-
-	result := sql
-	for _, keyword := range keywords {
-		pattern := `(?i)\b` + regexp.QuoteMeta(keyword) + `\b`
-		re := regexp.MustCompile(pattern)
-		result = re.ReplaceAllString(result, f.keyword(keyword))
-	}
-
-	return result
-}
-
 // keyword 处理关键字大小写
 func (f *Formatter) keyword(word string) string {
 	if f.KeywordUpper {
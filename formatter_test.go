@@ -3,6 +3,8 @@ package sqlformatter
 import (
 	"strings"
 	"testing"
+
+	"github.com/BruceDu521/sql-formatter/dialect"
 )
 
 func TestNewFormatter(t *testing.T) {
@@ -54,7 +56,8 @@ FROM
   users u
   JOIN products p on u.id = p.user_id
 WHERE
-  u.age > 25 and p.category = 'electronics'
+  u.age > 25
+  AND p.category = 'electronics'
 GROUP BY
   u.id
 ORDER BY
@@ -75,6 +78,18 @@ GROUP BY
 HAVING
   COUNT(*) > 5`,
 		},
+		{
+			name:  "Nested prefix operators",
+			input: "select - -a, b from t where x = - -1 and y = 2",
+			expected: `SELECT
+  - -a,
+  b
+FROM
+  t
+WHERE
+  x = - -1
+  AND y = 2`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -114,6 +129,42 @@ VALUES
 VALUES
   ('Laptop', 999.99, 'electronics', 'High-performance laptop')`,
 		},
+		{
+			name:  "Multi-row INSERT",
+			input: "INSERT INTO events (id, created_at) VALUES (1, now()), (2, now() - interval '1 day'), (3, DEFAULT)",
+			expected: `INSERT INTO events
+  (id, created_at)
+VALUES
+  (1, now()),
+  (2, now() - interval '1 day'),
+  (3, DEFAULT)`,
+		},
+		{
+			name:  "INSERT without column list",
+			input: "insert into users values (1, 'Alice')",
+			expected: `INSERT INTO users
+VALUES
+  (1, 'Alice')`,
+		},
+		{
+			name:  "INSERT ... SELECT",
+			input: "insert into archived_users (id, name) select id, name from users where deleted_at is not null",
+			expected: `INSERT INTO archived_users
+  (id, name)
+SELECT
+  id,
+  name
+FROM
+  users
+WHERE
+  deleted_at is not null`,
+		},
+		{
+			name:  "DEFAULT VALUES",
+			input: "insert into audit_log default values",
+			expected: `INSERT INTO audit_log
+DEFAULT VALUES`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -163,6 +214,41 @@ WHERE
 SET
   active = true`,
 		},
+		{
+			name:  "UPDATE with JOIN",
+			input: "UPDATE orders o JOIN customers c ON c.id = o.customer_id SET o.region = c.region WHERE o.region IS NULL",
+			expected: `UPDATE
+  orders o
+  JOIN customers c ON c.id = o.customer_id
+SET
+  o.region = c.region
+WHERE
+  o.region IS NULL`,
+		},
+		{
+			name:  "UPDATE ... FROM",
+			input: "update accounts set balance = a.balance + t.amount from transfers t where t.account_id = accounts.id",
+			expected: `UPDATE accounts
+SET
+  balance = a.balance + t.amount
+FROM
+  transfers t
+WHERE
+  t.account_id = accounts.id`,
+		},
+		{
+			name:  "UPDATE with ORDER BY and LIMIT",
+			input: "update jobs set claimed = 1 where claimed = 0 order by priority desc limit 10",
+			expected: `UPDATE jobs
+SET
+  claimed = 1
+WHERE
+  claimed = 0
+ORDER BY
+  priority desc
+LIMIT
+  10`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -198,7 +284,39 @@ WHERE
 			input: "DELETE FROM orders WHERE status = 'cancelled' AND created_at < '2023-01-01'",
 			expected: `DELETE FROM orders
 WHERE
-  status = 'cancelled' AND created_at < '2023-01-01'`,
+  status = 'cancelled'
+  AND created_at < '2023-01-01'`,
+		},
+		{
+			name:  "DELETE ... USING",
+			input: "delete from orders using customers c where c.id = orders.customer_id and c.banned",
+			expected: `DELETE FROM orders
+USING
+  customers c
+WHERE
+  c.id = orders.customer_id
+  AND c.banned`,
+		},
+		{
+			name:  "MySQL multi-table DELETE",
+			input: "DELETE o, i FROM orders o LEFT JOIN items i ON i.order_id = o.id WHERE o.status = 'void'",
+			expected: `DELETE o, i
+FROM
+  orders o
+  LEFT JOIN items i ON i.order_id = o.id
+WHERE
+  o.status = 'void'`,
+		},
+		{
+			name:  "DELETE with ORDER BY and LIMIT",
+			input: "delete from logs where level = 'debug' order by created_at limit 1000",
+			expected: `DELETE FROM logs
+WHERE
+  level = 'debug'
+ORDER BY
+  created_at
+LIMIT
+  1000`,
 		},
 	}
 
@@ -252,6 +370,148 @@ func TestFormatterOptions(t *testing.T) {
 	})
 }
 
+func TestScriptFormatting(t *testing.T) {
+	script := "select id from users; update users set active = 0 where id = 1; -- deactivate\ncreate table notes (body text);"
+
+	tests := []struct {
+		name       string
+		blankLines int
+		semicolon  SemicolonPolicy
+		expected   string
+	}{
+		{
+			name:       "Default spacing keeps trailing semicolon",
+			blankLines: 1,
+			semicolon:  SemicolonPreserve,
+			expected: `SELECT
+  id
+FROM
+  users;
+
+UPDATE users
+SET
+  active = 0
+WHERE
+  id = 1; -- deactivate
+
+CREATE TABLE notes (
+  body text
+);`,
+		},
+		{
+			name:       "No blank lines and no trailing semicolon",
+			blankLines: 0,
+			semicolon:  SemicolonNever,
+			expected: `SELECT
+  id
+FROM
+  users;
+UPDATE users
+SET
+  active = 0
+WHERE
+  id = 1; -- deactivate
+CREATE TABLE notes (
+  body text
+)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.BlankLines = tt.blankLines
+			formatter.Semicolon = tt.semicolon
+			result, err := formatter.Format(script)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("Always add trailing semicolon", func(t *testing.T) {
+		formatter := NewFormatter()
+		formatter.Semicolon = SemicolonAlways
+		result, err := formatter.Format("delete from logs")
+		if err != nil {
+			t.Fatalf("Formatting failed: %v", err)
+		}
+		if !strings.HasSuffix(result, ";") {
+			t.Errorf("Expected trailing semicolon, actual result: %s", result)
+		}
+	})
+}
+
+func TestCommentBeforeTerminator(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Comment inside WHERE",
+			input: "SELECT a FROM t WHERE x -- c\n = 1; SELECT 2",
+			expected: `SELECT
+  a
+FROM
+  t
+WHERE
+  x = 1 -- c
+;
+
+SELECT
+  2`,
+		},
+		{
+			name:  "Comment inside SET",
+			input: "UPDATE t SET a = -- c\n 1; select 2;",
+			expected: `UPDATE t
+SET
+  a = 1 -- c
+;
+
+SELECT
+  2;`,
+		},
+		{
+			name:  "Comment inside VALUES",
+			input: "INSERT INTO t VALUES (1, -- c\n 2); select 2",
+			expected: `INSERT INTO t
+VALUES
+  (1, 2) -- c
+;
+
+SELECT
+  2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+			// 再次格式化时语句不能合并
+			again, err := formatter.Format(result)
+			if err != nil {
+				t.Fatalf("Reformat failed: %v", err)
+			}
+			if again != result {
+				t.Errorf("Reformatting changed the output:\n%s", again)
+			}
+		})
+	}
+}
+
 func TestErrorCases(t *testing.T) {
 	formatter := NewFormatter()
 
@@ -279,6 +539,1704 @@ func TestErrorCases(t *testing.T) {
 	}
 }
 
+func TestLiteralsAreNotRewritten(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Keywords inside string literal",
+			input: "select id from notes where body = 'select from where'",
+			expected: `SELECT
+  id
+FROM
+  notes
+WHERE
+  body = 'select from where'`,
+		},
+		{
+			name:  "Keywords as quoted identifiers",
+			input: "select \"from\", `where` from t",
+			expected: `SELECT
+  "from",
+  ` + "`where`" + `
+FROM
+  t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpsertFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "ON CONFLICT DO UPDATE",
+			input: "insert into users (id, name) values (1, 'Alice') on conflict (id) do update set name = excluded.name, updated_at = now() where users.locked = false",
+			expected: `INSERT INTO users
+  (id, name)
+VALUES
+  (1, 'Alice')
+ON CONFLICT (id) DO UPDATE SET
+  name = excluded.name,
+  updated_at = now()
+WHERE
+  users.locked = false`,
+		},
+		{
+			name:  "ON CONFLICT DO NOTHING",
+			input: "insert into tags (name) values ('go') on conflict on constraint tags_name_key do nothing",
+			expected: `INSERT INTO tags
+  (name)
+VALUES
+  ('go')
+ON CONFLICT ON CONSTRAINT tags_name_key DO NOTHING`,
+		},
+		{
+			name:  "ON DUPLICATE KEY UPDATE",
+			input: "insert into counters (id, hits) values (1, 1) on duplicate key update hits = hits + values(hits)",
+			expected: `INSERT INTO counters
+  (id, hits)
+VALUES
+  (1, 1)
+ON DUPLICATE KEY UPDATE
+  hits = hits + values(hits)`,
+		},
+		{
+			name:  "REPLACE INTO",
+			input: "replace into settings (name, value) values ('theme', 'dark')",
+			expected: `REPLACE INTO settings
+  (name, value)
+VALUES
+  ('theme', 'dark')`,
+		},
+		{
+			name:  "INSERT IGNORE",
+			input: "insert ignore into visits (user_id, day) select user_id, day from staging",
+			expected: `INSERT IGNORE INTO visits
+  (user_id, day)
+SELECT
+  user_id,
+  day
+FROM
+  staging`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReturningFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "UPDATE ... RETURNING",
+			input: "update users set name = 'Bob' where id = 1 returning id, name as new_name",
+			expected: `UPDATE users
+SET
+  name = 'Bob'
+WHERE
+  id = 1
+RETURNING
+  id,
+  name as new_name`,
+		},
+		{
+			name:  "DELETE ... RETURNING",
+			input: "delete from sessions where expires_at < now() returning *",
+			expected: `DELETE FROM sessions
+WHERE
+  expires_at < now()
+RETURNING
+  *`,
+		},
+		{
+			name:  "INSERT ... RETURNING after ON CONFLICT",
+			input: "insert into tags (name) values ('go') on conflict do nothing returning id",
+			expected: `INSERT INTO tags
+  (name)
+VALUES
+  ('go')
+ON CONFLICT DO NOTHING
+RETURNING
+  id`,
+		},
+		{
+			name:  "SQL Server OUTPUT INTO",
+			input: "INSERT INTO orders (total) OUTPUT inserted.id, inserted.total INTO audit (id, total) VALUES (10)",
+			expected: `INSERT INTO orders
+  (total)
+OUTPUT
+  inserted.id,
+  inserted.total
+INTO audit (id, total)
+VALUES
+  (10)`,
+		},
+		{
+			name:  "SQL Server OUTPUT on DELETE",
+			input: "DELETE FROM sessions OUTPUT deleted.* WHERE expired = 1",
+			expected: `DELETE FROM sessions
+OUTPUT
+  deleted.*
+WHERE
+  expired = 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMergeFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Matched and not matched branches",
+			input: "merge into customers t using staging s on t.id = s.id when matched and s.deleted = 1 then delete when matched then update set name = s.name, updated_at = now() when not matched then insert (id, name) values (s.id, s.name)",
+			expected: `MERGE INTO customers t
+USING staging s
+ON t.id = s.id
+WHEN MATCHED AND s.deleted = 1 THEN
+  DELETE
+WHEN MATCHED THEN
+  UPDATE SET
+    name = s.name,
+    updated_at = now()
+WHEN NOT MATCHED THEN
+  INSERT (id, name)
+  VALUES (s.id, s.name)`,
+		},
+		{
+			name:  "Source subquery and SQL Server branches",
+			input: "MERGE INTO inventory AS i USING (SELECT sku, qty FROM deliveries) AS d ON i.sku = d.sku WHEN NOT MATCHED BY TARGET THEN INSERT (sku, qty) VALUES (d.sku, d.qty) WHEN NOT MATCHED BY SOURCE THEN DELETE OUTPUT deleted.sku;",
+			expected: `MERGE INTO inventory AS i
+USING (
+  SELECT
+    sku,
+    qty
+  FROM
+    deliveries
+) AS d
+ON i.sku = d.sku
+WHEN NOT MATCHED BY TARGET THEN
+  INSERT (sku, qty)
+  VALUES (d.sku, d.qty)
+WHEN NOT MATCHED BY SOURCE THEN
+  DELETE
+OUTPUT
+  deleted.sku;`,
+		},
+		{
+			name:  "DO NOTHING and DEFAULT VALUES",
+			input: "merge into t using s on t.id = s.id when matched then do nothing when not matched then insert default values",
+			expected: `MERGE INTO t
+USING s
+ON t.id = s.id
+WHEN MATCHED THEN
+  DO NOTHING
+WHEN NOT MATCHED THEN
+  INSERT DEFAULT VALUES`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSubqueryFormatting(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Derived table",
+			input: "SELECT * FROM (SELECT id FROM t WHERE x) s WHERE y",
+			expected: `SELECT
+  *
+FROM
+  (
+    SELECT
+      id
+    FROM
+      t
+    WHERE
+      x
+  ) s
+WHERE
+  y`,
+		},
+		{
+			name:  "Nested IN and EXISTS",
+			input: "select id from a where id in (select a_id from b where exists (select 1 from c where c.b_id = b.id))",
+			expected: `SELECT
+  id
+FROM
+  a
+WHERE
+  id in (
+    SELECT
+      a_id
+    FROM
+      b
+    WHERE
+      exists (
+        SELECT
+          1
+        FROM
+          c
+        WHERE
+          c.b_id = b.id
+      )
+  )`,
+		},
+		{
+			name:  "Scalar subquery and join target",
+			input: "select u.id, (select count(*) from orders o where o.user_id = u.id) as total from users u left join (select user_id from bans) b on b.user_id = u.id",
+			expected: `SELECT
+  u.id,
+  (
+    SELECT
+      count(*)
+    FROM
+      orders o
+    WHERE
+      o.user_id = u.id
+  ) as total
+FROM
+  users u
+  LEFT JOIN (
+    SELECT
+      user_id
+    FROM
+      bans
+  ) b on b.user_id = u.id`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWithFormatting(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Multiple CTEs",
+			input: "with active as (select id from users where active = 1), totals (user_id, total) as (select user_id, sum(amount) from orders group by user_id) select a.id, t.total from active a join totals t on t.user_id = a.id",
+			expected: `WITH active as (
+  SELECT
+    id
+  FROM
+    users
+  WHERE
+    active = 1
+),
+totals (user_id, total) as (
+  SELECT
+    user_id,
+    sum(amount)
+  FROM
+    orders
+  GROUP BY
+    user_id
+)
+SELECT
+  a.id,
+  t.total
+FROM
+  active a
+  JOIN totals t on t.user_id = a.id`,
+		},
+		{
+			name:  "Recursive CTE inside a subquery",
+			input: "select * from (with recursive r as (select 1 as n) select n from r) x",
+			expected: `SELECT
+  *
+FROM
+  (
+    WITH RECURSIVE r as (
+      SELECT
+        1 as n
+    )
+    SELECT
+      n
+    FROM
+      r
+  ) x`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSetOperationFormatting(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "UNION ALL with trailing ORDER BY and LIMIT",
+			input: "select id from customers union all select id from suppliers where active = 1 order by id limit 10",
+			expected: `SELECT
+  id
+FROM
+  customers
+UNION ALL
+SELECT
+  id
+FROM
+  suppliers
+WHERE
+  active = 1
+ORDER BY
+  id
+LIMIT
+  10`,
+		},
+		{
+			name:  "Parenthesized branches",
+			input: "(select a from t order by a limit 1) except (select a from u)",
+			expected: `(
+  SELECT
+    a
+  FROM
+    t
+  ORDER BY
+    a
+  LIMIT
+    1
+)
+EXCEPT
+(
+  SELECT
+    a
+  FROM
+    u
+)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCaseFormatting(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		inlineWidth int
+		expected    string
+	}{
+		{
+			name:  "CASE laid out as a block",
+			input: "select id, case when age < 18 then 'minor' else 'adult' end as bracket from users",
+			expected: `SELECT
+  id,
+  CASE
+    WHEN age < 18 THEN 'minor'
+    ELSE 'adult'
+  END as bracket
+FROM
+  users`,
+		},
+		{
+			name:  "Nested CASE",
+			input: "select case when a = 1 then case b when 2 then 'x' else 'y' end else 'z' end from t",
+			expected: `SELECT
+  CASE
+    WHEN a = 1 THEN CASE b
+      WHEN 2 THEN 'x'
+      ELSE 'y'
+    END
+    ELSE 'z'
+  END
+FROM
+  t`,
+		},
+		{
+			name:        "Short CASE kept inline",
+			input:       "select case when ok then 1 else 0 end as flag, case when a = 1 then 'one' when a = 2 then 'two' else 'many' end from t",
+			inlineWidth: 40,
+			expected: `SELECT
+  CASE WHEN ok THEN 1 ELSE 0 END as flag,
+  CASE
+    WHEN a = 1 THEN 'one'
+    WHEN a = 2 THEN 'two'
+    ELSE 'many'
+  END
+FROM
+  t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.CaseInlineWidth = tt.inlineWidth
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestConditionFormatting(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		placement OperatorPlacement
+		expected  string
+	}{
+		{
+			name:  "Parenthesized group and BETWEEN",
+			input: "select id from orders where created_at between '2024-01-01' and '2024-12-31' and (status = 'paid' or status = 'shipped') and not (total < 10 or total is null)",
+			expected: `SELECT
+  id
+FROM
+  orders
+WHERE
+  created_at between '2024-01-01' and '2024-12-31'
+  AND (
+    status = 'paid'
+    OR status = 'shipped'
+  )
+  AND not (
+    total < 10
+    OR total is null
+  )`,
+		},
+		{
+			name:      "Trailing operators in HAVING",
+			input:     "select user_id from orders group by user_id having count(*) > 1 and sum(total) > 100",
+			placement: OperatorTrailing,
+			expected: `SELECT
+  user_id
+FROM
+  orders
+GROUP BY
+  user_id
+HAVING
+  count(*) > 1 AND
+  sum(total) > 100`,
+		},
+		{
+			name:  "UPDATE condition",
+			input: "update users set active = 0 where last_login < '2020-01-01' or banned = 1",
+			expected: `UPDATE users
+SET
+  active = 0
+WHERE
+  last_login < '2020-01-01'
+  OR banned = 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.LogicalOperators = tt.placement
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestJoinFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		newline  bool
+		split    bool
+		expected string
+	}{
+		{
+			name:  "All join kinds",
+			input: "select * from a left outer join b on a.id = b.a_id cross join c natural join d full outer join e using (id) straight_join f, g",
+			expected: `SELECT
+  *
+FROM
+  a
+  LEFT OUTER JOIN b on a.id = b.a_id
+  CROSS JOIN c
+  NATURAL JOIN d
+  FULL OUTER JOIN e using (id)
+  STRAIGHT_JOIN f,
+  g`,
+		},
+		{
+			name:  "LATERAL subquery and function",
+			input: "select * from users u join lateral (select id from orders o where o.user_id = u.id limit 1) o on true, lateral unnest(u.tags) as t(tag)",
+			expected: `SELECT
+  *
+FROM
+  users u
+  JOIN lateral (
+    SELECT
+      id
+    FROM
+      orders o
+    WHERE
+      o.user_id = u.id
+    LIMIT
+      1
+  ) o on true,
+  lateral unnest(u.tags) as t (tag)`,
+		},
+		{
+			name:    "ON on its own line split on AND",
+			input:   "select * from orders o join customers c on c.id = o.customer_id and c.active = 1 join regions r using (region_id)",
+			newline: true,
+			split:   true,
+			expected: `SELECT
+  *
+FROM
+  orders o
+  JOIN customers c
+    on c.id = o.customer_id
+    AND c.active = 1
+  JOIN regions r
+    using (region_id)`,
+		},
+		{
+			name:  "Split ON kept on the join line",
+			input: "select * from a join b on a.id = b.id or a.key = b.key",
+			split: true,
+			expected: `SELECT
+  *
+FROM
+  a
+  JOIN b on a.id = b.id
+    OR a.key = b.key`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.JoinConditionNewline = tt.newline
+			formatter.SplitJoinConditions = tt.split
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWindowFormatting(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		inlineWidth int
+		expected    string
+	}{
+		{
+			name:        "Long window spec broken inside parentheses",
+			input:       "select a, b, row_number() over (partition by a order by b rows between unbounded preceding and current row) as rn, c from t",
+			inlineWidth: 60,
+			expected: `SELECT
+  a,
+  b,
+  row_number() OVER (
+    PARTITION BY a
+    ORDER BY b
+    ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+  ) as rn,
+  c
+FROM
+  t`,
+		},
+		{
+			name:        "Short spec and named window",
+			input:       "select rank() over (order by score desc), sum(x) over w from t window w as (partition by a range between 1 preceding and 1 following)",
+			inlineWidth: 60,
+			expected: `SELECT
+  rank() OVER (ORDER BY score desc),
+  sum(x) OVER w
+FROM
+  t
+WINDOW
+  w as (PARTITION BY a RANGE BETWEEN 1 PRECEDING AND 1 FOLLOWING)`,
+		},
+		{
+			name:  "Always break",
+			input: "select lag(x) over (partition by a) from t",
+			expected: `SELECT
+  lag(x) OVER (
+    PARTITION BY a
+  )
+FROM
+  t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.WindowInlineWidth = tt.inlineWidth
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCreateTableFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		align    bool
+		expected string
+	}{
+		{
+			name:  "Columns and table constraints",
+			input: "create table if not exists users (id bigint not null, org_id int references orgs (id), email varchar(255) unique, primary key (id), constraint chk_email check (email like '%@%'))",
+			expected: `CREATE TABLE IF NOT EXISTS users (
+  id bigint not null,
+  org_id int references orgs (id),
+  email varchar(255) unique,
+  primary key (id),
+  constraint chk_email check (email like '%@%')
+)`,
+		},
+		{
+			name:  "Aligned columns",
+			input: "CREATE TABLE orders (id BIGINT NOT NULL AUTO_INCREMENT, -- surrogate key\ncustomer_id INT NOT NULL, total DECIMAL(10, 2) DEFAULT 0, note TEXT, PRIMARY KEY (id)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			align: true,
+			expected: `CREATE TABLE orders (
+  id          BIGINT         NOT NULL AUTO_INCREMENT, -- surrogate key
+  customer_id INT            NOT NULL,
+  total       DECIMAL(10, 2) DEFAULT 0,
+  note        TEXT,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+		},
+		{
+			name:  "Temporary table with partitioning",
+			input: "create temporary table events (id int, created_at timestamp) partition by range (created_at)",
+			expected: `CREATE TEMPORARY TABLE events (
+  id int,
+  created_at timestamp
+)
+PARTITION BY range (created_at)`,
+		},
+		{
+			name:  "Create table as query",
+			input: "create table recent as select id from orders where created_at > '2024-01-01'",
+			expected: `CREATE TABLE recent as
+SELECT
+  id
+FROM
+  orders
+WHERE
+  created_at > '2024-01-01'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.AlignColumns = tt.align
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAlterTableFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Multiple actions",
+			input: "alter table users add column email varchar(255) not null default '', drop column if exists legacy cascade, alter column name set not null, rename column login to username",
+			expected: `ALTER TABLE users
+  ADD COLUMN email varchar(255) not null default '',
+  DROP COLUMN IF EXISTS legacy cascade,
+  ALTER COLUMN name set not null,
+  RENAME COLUMN login to username`,
+		},
+		{
+			name:  "Constraints",
+			input: "ALTER TABLE orders ADD CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers (id), -- enforce ownership\nDROP CONSTRAINT old_fk",
+			expected: `ALTER TABLE orders
+  ADD CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers (id), -- enforce ownership
+  DROP CONSTRAINT old_fk`,
+		},
+		{
+			name:  "Rename table",
+			input: "alter table if exists users rename to members",
+			expected: `ALTER TABLE IF EXISTS users
+  RENAME TO members`,
+		},
+		{
+			name:  "MySQL modify column",
+			input: "alter table users modify column age int unsigned not null, drop primary key",
+			expected: `ALTER TABLE users
+  MODIFY COLUMN age int unsigned not null,
+  DROP primary key`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDDLFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Partial unique index",
+			input: "create unique index concurrently if not exists idx_users_email on users using btree (lower(email)) include (name) where deleted_at is null",
+			expected: `CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_email on users using btree (lower(email))
+INCLUDE (name)
+WHERE
+  deleted_at is null`,
+		},
+		{
+			name:  "View reuses the SELECT layout",
+			input: "create or replace view active_users (id, name) as select id, name from users where active = 1 with check option",
+			expected: `CREATE OR REPLACE VIEW active_users (id, name) as
+SELECT
+  id,
+  name
+FROM
+  users
+WHERE
+  active = 1
+with check option`,
+		},
+		{
+			name:  "Materialized view",
+			input: "create materialized view if not exists daily_totals as select day, sum(total) from orders group by day with no data",
+			expected: `CREATE MATERIALIZED VIEW IF NOT EXISTS daily_totals as
+SELECT
+  day,
+  sum(total)
+FROM
+  orders
+GROUP BY
+  day
+with no data`,
+		},
+		{
+			name:  "Sequence options one per line",
+			input: "create sequence order_seq as bigint start with 1000 increment by 1 no maxvalue cache 20",
+			expected: `CREATE SEQUENCE order_seq
+  as bigint
+  start with 1000
+  increment by 1
+  no maxvalue
+  cache 20`,
+		},
+		{
+			name:  "Alter sequence",
+			input: "alter sequence if exists order_seq restart with 1",
+			expected: `ALTER SEQUENCE IF EXISTS order_seq
+  restart with 1`,
+		},
+		{
+			name:     "Create schema",
+			input:    "create schema if not exists reporting authorization analyst",
+			expected: "CREATE SCHEMA IF NOT EXISTS reporting authorization analyst",
+		},
+		{
+			name:     "Drop with IF EXISTS and CASCADE",
+			input:    "drop materialized view if exists daily_totals, weekly_totals cascade",
+			expected: "DROP MATERIALIZED VIEW IF EXISTS daily_totals, weekly_totals CASCADE",
+		},
+		{
+			name:     "Drop index",
+			input:    "drop index concurrently idx_users_email",
+			expected: "DROP INDEX CONCURRENTLY idx_users_email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCommentFormatting(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Line comment does not swallow the query",
+			input: "-- active users\nselect id from users where active = 1",
+			expected: `-- active users
+SELECT
+  id
+FROM
+  users
+WHERE
+  active = 1`,
+		},
+		{
+			name:  "Trailing comments stay on their line",
+			input: "select id, -- primary key\n name /* display */\nfrom users",
+			expected: `SELECT
+  id, -- primary key
+  name /* display */
+FROM
+  users`,
+		},
+		{
+			name:  "Own-line comment before a clause",
+			input: "select id from users\n/* only admins */\nwhere role = 'admin'",
+			expected: `SELECT
+  id
+FROM
+  users
+/* only admins */
+WHERE
+  role = 'admin'`,
+		},
+		{
+			name:  "Comments between statements",
+			input: "delete from a where id = 1; -- cleanup\n-- reset counters\nupdate b set n = 0",
+			expected: `DELETE FROM a
+WHERE
+  id = 1; -- cleanup
+
+-- reset counters
+UPDATE b
+SET
+  n = 0`,
+		},
+		{
+			name:     "Unparsed SQL keeps line comments terminated",
+			input:    "vacuum analyze users -- nightly\n-- done",
+			expected: "vacuum analyze users -- nightly\n-- done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Formatting failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Formatting result mismatch\nExpected:\n%s\nActual:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// bracketDialect 测试用方言：方括号标识符、#注释、保留字SAMPLE
+type bracketDialect struct {
+	dialect.Generic
+}
+
+func (bracketDialect) Name() string             { return "bracket" }
+func (bracketDialect) Keywords() []string       { return []string{"SAMPLE"} }
+func (bracketDialect) Reserved() []string       { return []string{"SAMPLE"} }
+func (bracketDialect) IdentifierQuotes() string { return "[" }
+func (bracketDialect) LineComments() []string   { return []string{"#"} }
+
+func TestDialectFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  dialect.Dialect
+		input    string
+		expected string
+	}{
+		{
+			name:  "Generic by default",
+			input: "select \"order id\" from t -- c",
+			expected: `SELECT
+  "order id"
+FROM
+  t -- c`,
+		},
+		{
+			name:    "Dialect identifier quotes and comments",
+			dialect: bracketDialect{},
+			input:   "select [order id] from t # c",
+			expected: `SELECT
+  [order id]
+FROM
+  t # c`,
+		},
+		{
+			name:     "Dialect reserved words are not aliases",
+			dialect:  bracketDialect{},
+			input:    "select a sample from t",
+			expected: "SELECT a sample FROM t",
+		},
+		{
+			name:    "Generic dialect treats the same word as an alias",
+			dialect: dialect.Generic{},
+			input:   "select a sample from t",
+			expected: `SELECT
+  a sample
+FROM
+  t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.Dialect = tt.dialect
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostgresFormatting(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.Dialect = dialect.Postgres{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Casts without spaces",
+			input: "select a :: int, '{1}'::text[], -b::numeric(10, 2) from t",
+			expected: `SELECT
+  a::int,
+  '{1}'::text[],
+  -b::numeric(10, 2)
+FROM
+  t`,
+		},
+		{
+			name:  "Arrays and subscripts",
+			input: "select array[1,2], tags[1], tags[2:3] from t",
+			expected: `SELECT
+  ARRAY[1, 2],
+  tags[1],
+  tags[2:3]
+FROM
+  t`,
+		},
+		{
+			name:  "JSON and containment operators",
+			input: "select data->>'name' from t where data #> '{a,b}' is not null and tags @> array['x'] and data ?| array['a','b']",
+			expected: `SELECT
+  data ->> 'name'
+FROM
+  t
+WHERE
+  data #> '{a,b}' is not null
+  AND tags @> ARRAY['x']
+  AND data ?| ARRAY['a', 'b']`,
+		},
+		{
+			name:  "Escape strings, ILIKE and parameters",
+			input: "select E'it\\'s' from t where name ilike $1",
+			expected: `SELECT
+  E'it\'s'
+FROM
+  t
+WHERE
+  name ilike $1`,
+		},
+		{
+			name:  "DISTINCT ON",
+			input: "select distinct on (user_id) user_id, created_at from events order by user_id, created_at desc",
+			expected: `SELECT DISTINCT ON (user_id)
+  user_id,
+  created_at
+FROM
+  events
+ORDER BY
+  user_id, created_at desc`,
+		},
+		{
+			name:  "Dollar-quoted bodies are kept untouched",
+			input: "create function one() returns int as $body$\n  select   1;  -- one\n$body$ language sql;\nselect one()",
+			expected: `create function one() returns int as $body$
+  select   1;  -- one
+$body$ language sql;
+
+SELECT
+  one()`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMySQLFormatting(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.Dialect = dialect.MySQL{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Backticks, double-quoted strings and backslash escapes",
+			input:    "select `order id`, \"it\\\"s\", 'a\\'b' from `my table`",
+			expected: "SELECT\n  `order id`,\n  \"it\\\"s\",\n  'a\\'b'\nFROM\n  `my table`",
+		},
+		{
+			name:  "Hash and versioned comments",
+			input: "select /*!40001 SQL_NO_CACHE */ id from users # all users\nwhere id = ?",
+			expected: `SELECT /*!40001 SQL_NO_CACHE */
+  id
+FROM
+  users # all users
+WHERE
+  id = ?`,
+		},
+		{
+			name:  "Index hints",
+			input: "select * from orders o use index (idx_user) join users u force index for join (PRIMARY) on o.user_id = u.id",
+			expected: `SELECT
+  *
+FROM
+  orders o USE INDEX (idx_user)
+  JOIN users u FORCE INDEX FOR JOIN (PRIMARY) on o.user_id = u.id`,
+		},
+		{
+			name:  "LIMIT offset, count",
+			input: "select id from users order by id limit 20, 10",
+			expected: `SELECT
+  id
+FROM
+  users
+ORDER BY
+  id
+LIMIT
+  20, 10`,
+		},
+		{
+			name:  "DELIMITER directives",
+			input: "DELIMITER $$\nCREATE PROCEDURE p() BEGIN select 1; END$$\nDELIMITER ;\nselect 2;",
+			expected: `DELIMITER $$
+
+CREATE PROCEDURE p() BEGIN SELECT 1; END$$
+
+DELIMITER ;
+
+SELECT
+  2;`,
+		},
+		{
+			name:  "Hash comments in statements that do not parse",
+			input: "GRANT SELECT # read only\nON db.* TO u",
+			expected: `GRANT SELECT # read only
+ON db.* TO u`,
+		},
+		{
+			name: "Procedure bodies keep their line breaks",
+			input: `DELIMITER //
+CREATE PROCEDURE p(x INT)
+BEGIN
+  DECLARE y INT;
+  IF x > 0 THEN
+    SET y = x; # positive
+  END IF;
+  SELECT y;
+END //
+DELIMITER ;`,
+			expected: `DELIMITER //
+
+CREATE PROCEDURE p(x INT)
+BEGIN
+  DECLARE y INT;
+  IF x > 0 THEN
+    SET y = x; # positive
+  END IF;
+  SELECT y;
+END//
+
+DELIMITER ;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTSQLFormatting(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.Dialect = dialect.TSQL{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Brackets, TOP and N strings",
+			input: "select top (10) percent with ties [o].[id], N'abc' as [x y] from [dbo].[orders] o",
+			expected: `SELECT TOP (10) PERCENT WITH TIES
+  [o].[id],
+  N'abc' as [x y]
+FROM
+  [dbo].[orders] o`,
+		},
+		{
+			name:  "Variables, DECLARE and SET",
+			input: "declare @id int = 1, @name nvarchar(50); set @id += @@rowcount; set nocount on",
+			expected: `DECLARE
+  @id int = 1,
+  @name nvarchar(50);
+
+SET @id += @@rowcount;
+
+SET nocount on`,
+		},
+		{
+			name:  "OFFSET and FETCH",
+			input: "select id from users order by id offset 10 rows fetch next 5 rows only",
+			expected: `SELECT
+  id
+FROM
+  users
+ORDER BY
+  id
+OFFSET
+  10 ROWS
+FETCH NEXT
+  5 ROWS ONLY`,
+		},
+		{
+			name:  "CROSS and OUTER APPLY",
+			input: "select * from orders o cross apply dbo.items(o.id) i outer apply (select top 1 id from t) x",
+			expected: `SELECT
+  *
+FROM
+  orders o
+  CROSS APPLY dbo.items(o.id) i
+  OUTER APPLY (
+    SELECT TOP 1
+      id
+    FROM
+      t
+  ) x`,
+		},
+		{
+			name:  "GO batch separators",
+			input: "select 1\nGO\nselect 2\ngo 5",
+			expected: `SELECT
+  1
+GO
+
+SELECT
+  2
+go 5`,
+		},
+		{
+			name: "Procedure bodies stay in one batch",
+			input: `CREATE PROCEDURE dbo.p
+AS
+BEGIN
+  SET NOCOUNT ON;
+  SELECT a FROM t;
+  UPDATE t SET a = 1;
+END
+GO
+select 1`,
+			expected: `CREATE PROCEDURE dbo.p
+AS
+BEGIN
+  SET NOCOUNT ON;
+  SELECT a FROM t;
+  UPDATE t SET a = 1;
+END
+GO
+
+SELECT
+  1`,
+		},
+		{
+			name:  "OUTPUT INTO a table variable",
+			input: "insert into t (a) output inserted.id into @ids values (1); update t set a = 1 output inserted.a into @t where b = 1",
+			expected: `INSERT INTO t
+  (a)
+OUTPUT
+  inserted.id
+INTO @ids
+VALUES
+  (1);
+
+UPDATE t
+SET
+  a = 1
+OUTPUT
+  inserted.a
+INTO @t
+WHERE
+  b = 1`,
+		},
+		{
+			name:  "SET with a subquery",
+			input: "set @x = (select max(id) from t)",
+			expected: `SET @x = (
+  SELECT
+    max(id)
+  FROM
+    t
+)`,
+		},
+		{
+			name:  "OUTPUT $action",
+			input: "merge into t using s on t.id = s.id when matched then delete output $action, deleted.id;",
+			expected: `MERGE INTO t
+USING s
+ON t.id = s.id
+WHEN MATCHED THEN
+  DELETE
+OUTPUT
+  $action,
+  deleted.id;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOracleFormatting(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.Dialect = dialect.Oracle{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Q-quoted strings and outer join markers",
+			input: "select q'[it's]' note from emp e, dept d where e.dept_id = d.id(+) and rownum <= 10",
+			expected: `SELECT
+  q'[it's]' note
+FROM
+  emp e,
+  dept d
+WHERE
+  e.dept_id = d.id(+)
+  AND rownum <= 10`,
+		},
+		{
+			name:  "Hierarchical query",
+			input: "select id, level from emp start with mgr is null connect by nocycle prior id = mgr order siblings by name",
+			expected: `SELECT
+  id,
+  level
+FROM
+  emp
+START WITH
+  mgr is null
+CONNECT BY NOCYCLE
+  prior id = mgr
+ORDER SIBLINGS BY
+  name`,
+		},
+		{
+			name:  "MINUS and FETCH FIRST",
+			input: "select id from a minus select id from b order by id fetch first 5 rows only",
+			expected: `SELECT
+  id
+FROM
+  a
+MINUS
+SELECT
+  id
+FROM
+  b
+ORDER BY
+  id
+FETCH FIRST
+  5 ROWS ONLY`,
+		},
+		{
+			name: "Anonymous block with slash terminator",
+			input: "declare v_count number := 0; begin select count(*) into v_count from emp where dept_id = :dept; " +
+				"if v_count = 0 then raise no_data_found; end if; begin null; end; " +
+				"exception when no_data_found then null; when others then rollback; raise; end;\n/\nselect 1 from dual;",
+			expected: `DECLARE
+  v_count number := 0;
+BEGIN
+  SELECT
+    count(*)
+  INTO
+    v_count
+  FROM
+    emp
+  WHERE
+    dept_id = :dept;
+  IF v_count = 0 THEN
+    raise no_data_found;
+  END IF;
+  BEGIN
+    null;
+  END;
+EXCEPTION
+  WHEN no_data_found THEN
+    null;
+  WHEN others THEN
+    rollback;
+    raise;
+END;
+/
+
+SELECT
+  1
+FROM
+  dual;`,
+		},
+		{
+			name:  "Slash terminator without semicolon",
+			input: "INSERT INTO audit (id) VALUES (1)\n/\nselect total\n/ 2\nfrom t\n/\n",
+			expected: `INSERT INTO audit
+  (id)
+VALUES
+  (1)
+/
+
+SELECT
+  total / 2
+FROM
+  t
+/`,
+		},
+		{
+			name: "IF, LOOP and CASE statements indent their bodies",
+			input: `begin
+  IF v > 0 THEN
+    -- inside
+    y := 2;
+  elsif v < 0 then y := 3; else y := 4; end if;
+  for r in (select id from t) loop update t set a = r.id where id = r.id; end loop;
+  while v < 10 loop v := v + 1; end loop;
+  case v when 1 then null; else raise; end case;
+end;`,
+			expected: `BEGIN
+  IF v > 0 THEN
+    -- inside
+    y := 2;
+  ELSIF v < 0 THEN
+    y := 3;
+  ELSE
+    y := 4;
+  END IF;
+  FOR r in (select id from t) LOOP
+    UPDATE t
+    SET
+      a = r.id
+    WHERE
+      id = r.id;
+  END LOOP;
+  WHILE v < 10 LOOP
+    v := v + 1;
+  END LOOP;
+  CASE v
+    WHEN 1 THEN
+      null;
+    ELSE
+      raise;
+  END CASE;
+END;`,
+		},
+		{
+			name: "Stored procedures are not split at their semicolons",
+			input: `create or replace procedure p is
+begin
+  null;
+end;
+/
+select 1 from dual`,
+			expected: `create or replace procedure p is
+begin
+  null;
+END;
+/
+
+SELECT
+  1
+FROM
+  dual`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("Never keeps the semicolon after END", func(t *testing.T) {
+		formatter := NewFormatter()
+		formatter.Dialect = dialect.Oracle{}
+		formatter.Semicolon = SemicolonNever
+		result, err := formatter.Format("begin\n  null;\nend;\n/")
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		expected := `BEGIN
+  null;
+END;
+/`
+		if result != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestSQLiteFormatting(t *testing.T) {
+	formatter := NewFormatter()
+	formatter.Dialect = dialect.SQLite{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Pragmas",
+			input: "pragma foreign_keys = on; pragma main.table_info('users'); pragma optimize",
+			expected: `PRAGMA foreign_keys = on;
+
+PRAGMA main.table_info('users');
+
+PRAGMA optimize`,
+		},
+		{
+			name:  "Conflict clauses and parameters",
+			input: "insert or replace into users (id, name) values (?1, :name); update or rollback users set name = @name where id = $id",
+			expected: `INSERT OR REPLACE INTO users
+  (id, name)
+VALUES
+  (?1, :name);
+
+UPDATE OR ROLLBACK users
+SET
+  name = @name
+WHERE
+  id = $id`,
+		},
+		{
+			name:  "ATTACH and DETACH",
+			input: "attach database 'cache.db' as cache; detach cache",
+			expected: `ATTACH DATABASE 'cache.db' AS cache;
+
+DETACH cache`,
+		},
+		{
+			name:  "WITHOUT ROWID and STRICT tables",
+			input: "create table kv (k text primary key, v blob) without rowid, strict",
+			expected: `CREATE TABLE kv (
+  k text primary key,
+  v blob
+) without rowid, strict`,
+		},
+		{
+			name:  "Virtual tables",
+			input: "create virtual table if not exists docs using fts5(title, body, tokenize = 'porter')",
+			expected: `CREATE VIRTUAL TABLE IF NOT EXISTS docs USING fts5(
+  title,
+  body,
+  tokenize = 'porter'
+)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.Format(tt.input)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestSplitColumns(t *testing.T) {
 	formatter := NewFormatter()
 
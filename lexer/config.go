@@ -0,0 +1,70 @@
+package lexer
+
+// StringRules describes how string literals are written in a dialect
+type StringRules struct {
+	// Prefixes are letters glued to the opening quote, such as E, N or X;
+	// strings with the E prefix always accept backslash escapes
+	Prefixes []string
+	// BackslashEscapes lets a backslash escape the next character
+	BackslashEscapes bool
+	// DollarQuotes enables $$...$$ and $tag$...$tag$ strings
+	DollarQuotes bool
+	// DoubleQuotes makes "..." a string rather than a quoted identifier
+	DoubleQuotes bool
+	// QQuotes enables q'[...]' strings, which end at the chosen delimiter
+	// followed by a quote; [, {, ( and < are closed by their counterpart
+	QQuotes bool
+}
+
+// ScriptRules describes how a dialect's client tools end statements in a script
+type ScriptRules struct {
+	// DelimiterDirective recognises DELIMITER lines that change the
+	// statement terminator, as in the MySQL client
+	DelimiterDirective bool
+	// BatchSeparators are words that end a batch when they stand alone on
+	// a line, such as GO or the SQL*Plus /
+	BatchSeparators []string
+	// RepeatCount lets a batch separator be followed by a repeat count, as
+	// in GO 5
+	RepeatCount bool
+	// Blocks keeps a procedural block that starts with DECLARE or BEGIN in
+	// one statement up to the semicolon after its final END, as in PL/SQL
+	Blocks bool
+	// Routines keeps CREATE PROCEDURE, FUNCTION, PACKAGE and TRIGGER
+	// statements in one statement up to the END that closes their body;
+	// a body without BEGIN runs to the next batch separator
+	Routines bool
+}
+
+// Config holds the lexical rules of a SQL dialect
+type Config struct {
+	// Keywords are words tokenized as keywords in addition to the common ones
+	Keywords []string
+	// IdentifierQuotes are the characters that open a quoted identifier;
+	// [ is closed by ]
+	IdentifierQuotes string
+	// Strings describes string literals
+	Strings StringRules
+	// LineComments are the prefixes of comments running to the end of the
+	// line; /* ... */ block comments are always recognised
+	LineComments []string
+	// Placeholders are the characters that start a bind parameter: ? alone
+	// or followed by digits, $ followed by digits or a name, : and @
+	// followed by a name, @@ followed by a name
+	Placeholders string
+	// Operators are multi-character operators in addition to the common
+	// ones, longest first
+	Operators []string
+	// Scripts describes statement terminators in scripts
+	Scripts ScriptRules
+}
+
+// DefaultConfig returns the rules of the generic dialect
+func DefaultConfig() *Config {
+	return &Config{
+		IdentifierQuotes: "\"`",
+		Strings:          StringRules{DollarQuotes: true},
+		LineComments:     []string{"--"},
+		Placeholders:     "?$:",
+	}
+}
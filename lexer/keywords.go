@@ -0,0 +1,28 @@
+package lexer
+
+import "strings"
+
+// keywords 通用SQL关键字
+var keywords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		ADD ALL ALTER AND ANY AS ASC BETWEEN BY CASCADE CASE CAST CHANGE CHECK
+		COLUMN CONCURRENTLY CONFLICT CONSTRAINT CREATE CROSS CURRENT DEFAULT DELETE DESC
+		DISTINCT DO DROP DUPLICATE ELSE END ESCAPE EXCEPT EXISTS FALSE FETCH FIRST FOLLOWING
+		FOREIGN FROM FULL FULLTEXT GLOBAL GROUP GROUPS HAVING IF IGNORE IN INCLUDE INDEX
+		INNER INSERT INTERSECT INTO IS JOIN KEY LAST LATERAL LEFT LIKE LIMIT LOCAL
+		MATCHED MATERIALIZED MERGE MODIFY NATURAL NOT NOTHING NULL NULLS OFFSET ON ONLY OR ORDER OUTER OUTPUT
+		OVER PARTITION PRECEDING PRIMARY RANGE RECURSIVE REFERENCES RENAME REPLACE
+		RESTRICT RETURNING RIGHT ROW ROWS SCHEMA SELECT SEQUENCE SOURCE SET SPATIAL STRAIGHT_JOIN
+		TABLE TARGET TEMP TEMPORARY THEN TO TRUE UNBOUNDED UNION UNIQUE UNLOGGED UPDATE
+		USING VALUES VIEW WHEN WHERE WINDOW WITH
+	`) {
+		keywords[word] = true
+	}
+}
+
+// IsKeyword reports whether word is a known SQL keyword (case-insensitive)
+func IsKeyword(word string) bool {
+	return keywords[strings.ToUpper(word)]
+}
@@ -0,0 +1,460 @@
+// Package lexer splits SQL text into typed tokens with byte offsets.
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// multiCharOperators 多字符运算符，按长度优先匹配
+var multiCharOperators = []string{
+	"->>", "#>>", "<=>",
+	"<>", "!=", "<=", ">=", "||", "::", "->", "#>", "@>", "<@", "=>", "<<", ">>", "&&",
+}
+
+// Lexer produces tokens from SQL text
+type Lexer struct {
+	src       string
+	pos       int
+	cfg       *Config
+	keywords  map[string]bool // 方言额外的关键字
+	delimiter string          // DELIMITER指令设置的语句结束符，为空时使用分号
+}
+
+// New creates a lexer over the given SQL text using the generic rules
+func New(src string) *Lexer {
+	return NewWithConfig(src, DefaultConfig())
+}
+
+// NewWithConfig creates a lexer over the given SQL text using the rules of a dialect
+func NewWithConfig(src string, cfg *Config) *Lexer {
+	l := &Lexer{src: src, cfg: cfg, keywords: map[string]bool{}}
+	for _, word := range cfg.Keywords {
+		l.keywords[strings.ToUpper(word)] = true
+	}
+	return l
+}
+
+// Tokenize splits the SQL text into tokens, including whitespace and comments
+func Tokenize(src string) ([]Token, error) {
+	return TokenizeWithConfig(src, DefaultConfig())
+}
+
+// TokenizeWithConfig splits the SQL text into tokens using the rules of a dialect
+func TokenizeWithConfig(src string, cfg *Config) ([]Token, error) {
+	l := NewWithConfig(src, cfg)
+	var tokens []Token
+	for {
+		tok, ok, err := l.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return tokens, nil
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// Next returns the next token; ok is false once the input is exhausted
+func (l *Lexer) Next() (tok Token, ok bool, err error) {
+	if l.pos >= len(l.src) {
+		return Token{}, false, nil
+	}
+
+	start := l.pos
+	r := l.peekRune(0)
+	var typ TokenType
+
+	switch {
+	case unicode.IsSpace(r):
+		l.skipWhile(unicode.IsSpace)
+		typ = Whitespace
+	case l.isDelimiterDirective():
+		l.skipLine()
+		typ = Directive
+		l.delimiter = directiveDelimiter(l.src[start:l.pos])
+	case l.isBatchSeparator():
+		l.skipLine()
+		typ = Separator
+	case l.delimiter != "" && strings.HasPrefix(l.src[l.pos:], l.delimiter):
+		l.pos += len(l.delimiter)
+		typ = Delimiter
+	case l.isLineComment():
+		l.skipLine()
+		typ = Comment
+	case strings.HasPrefix(l.src[l.pos:], "/*"):
+		if err := l.skipBlockComment(); err != nil {
+			return Token{}, false, err
+		}
+		typ = Comment
+	case r == '\'':
+		if err := l.skipString(l.cfg.Strings.BackslashEscapes); err != nil {
+			return Token{}, false, err
+		}
+		typ = String
+	case r == '"' && l.cfg.Strings.DoubleQuotes:
+		if err := l.skipQuoted('"', l.cfg.Strings.BackslashEscapes); err != nil {
+			return Token{}, false, err
+		}
+		typ = String
+	case strings.ContainsRune(l.cfg.IdentifierQuotes, r):
+		if err := l.skipQuoted(closingQuote(r), false); err != nil {
+			return Token{}, false, err
+		}
+		typ = QuotedIdentifier
+	case isDigit(r) || (r == '.' && isDigit(l.peekRune(1))):
+		l.skipNumber()
+		typ = Number
+	case l.isQQuote():
+		if err := l.skipQQuote(); err != nil {
+			return Token{}, false, err
+		}
+		typ = String
+	case l.stringPrefix() != "":
+		prefix := l.stringPrefix()
+		l.pos += len(prefix)
+		if err := l.skipString(l.cfg.Strings.BackslashEscapes || strings.EqualFold(prefix, "E")); err != nil {
+			return Token{}, false, err
+		}
+		typ = String
+	case isIdentStart(r):
+		l.skipWhile(isIdentPart)
+		// 标识符可以包含$，不能吞掉紧跟其后的自定义结束符，如 END$$
+		if idx := strings.Index(l.src[start:l.pos], l.delimiter); l.delimiter != "" && idx > 0 {
+			l.pos = start + idx
+		}
+		typ = Identifier
+		if word := l.src[start:l.pos]; IsKeyword(word) || l.keywords[strings.ToUpper(word)] {
+			typ = Keyword
+		}
+	case r == '$' && l.cfg.Strings.DollarQuotes && l.dollarTag() != "":
+		if err := l.skipDollarQuoted(); err != nil {
+			return Token{}, false, err
+		}
+		typ = String
+	case strings.ContainsRune(l.cfg.Placeholders, r) && l.skipPlaceholder():
+		typ = Placeholder
+	case strings.ContainsRune("(),;.[]", r):
+		l.pos++
+		typ = Punctuation
+	default:
+		l.skipOperator()
+		typ = Operator
+	}
+
+	return Token{Type: typ, Value: l.src[start:l.pos], Start: start, End: l.pos}, true, nil
+}
+
+// peekRune 查看当前位置之后第n个字符
+func (l *Lexer) peekRune(n int) rune {
+	pos := l.pos
+	for i := 0; ; i++ {
+		if pos >= len(l.src) {
+			return utf8.RuneError
+		}
+		r, size := utf8.DecodeRuneInString(l.src[pos:])
+		if i == n {
+			return r
+		}
+		pos += size
+	}
+}
+
+// skipWhile 跳过满足条件的字符
+func (l *Lexer) skipWhile(pred func(rune) bool) {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !pred(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// skipLine 跳过到行尾（不含换行符）
+func (l *Lexer) skipLine() {
+	if idx := strings.IndexByte(l.src[l.pos:], '\n'); idx >= 0 {
+		l.pos += idx
+	} else {
+		l.pos = len(l.src)
+	}
+	// 保留\r\n中的\r给空白处理
+	if l.pos > 0 && l.src[l.pos-1] == '\r' {
+		l.pos--
+	}
+}
+
+// skipBlockComment 跳过块注释
+func (l *Lexer) skipBlockComment() error {
+	start := l.pos
+	idx := strings.Index(l.src[l.pos+2:], "*/")
+	if idx < 0 {
+		return fmt.Errorf("unterminated block comment at offset %d", start)
+	}
+	l.pos += idx + 4
+	return nil
+}
+
+// isDelimiterDirective 当前位置是否为行首的 DELIMITER xx 指令
+func (l *Lexer) isDelimiterDirective() bool {
+	if !l.cfg.Scripts.DelimiterDirective || !l.atLineStart() {
+		return false
+	}
+	rest := l.src[l.pos:]
+	const word = "DELIMITER"
+	if len(rest) <= len(word) || !strings.EqualFold(rest[:len(word)], word) || (rest[len(word)] != ' ' && rest[len(word)] != '\t') {
+		return false
+	}
+	return len(directiveFields(rest)) >= 2
+}
+
+// isBatchSeparator 当前行是否只有批分隔符（如GO），允许时可带重复次数
+func (l *Lexer) isBatchSeparator() bool {
+	if len(l.cfg.Scripts.BatchSeparators) == 0 || !l.atLineStart() {
+		return false
+	}
+	fields := directiveFields(l.src[l.pos:])
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 2 && l.cfg.Scripts.RepeatCount && strings.TrimLeftFunc(fields[1], isDigit) == "":
+	default:
+		return false
+	}
+	for _, separator := range l.cfg.Scripts.BatchSeparators {
+		if strings.EqualFold(fields[0], separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// atLineStart 当前位置之前同一行是否只有空白
+func (l *Lexer) atLineStart() bool {
+	lineStart := strings.LastIndexByte(l.src[:l.pos], '\n') + 1
+	return strings.TrimSpace(l.src[lineStart:l.pos]) == ""
+}
+
+// directiveFields 返回指令所在行按空白分隔的各部分
+func directiveFields(line string) []string {
+	if idx := strings.IndexByte(line, '\n'); idx >= 0 {
+		line = line[:idx]
+	}
+	return strings.Fields(line)
+}
+
+// directiveDelimiter 返回 DELIMITER 指令设置的结束符，设回分号时返回空串
+func directiveDelimiter(line string) string {
+	if fields := directiveFields(line); len(fields) >= 2 && fields[1] != ";" {
+		return fields[1]
+	}
+	return ""
+}
+
+// isLineComment 当前位置是否为行注释的开头
+func (l *Lexer) isLineComment() bool {
+	for _, prefix := range l.cfg.LineComments {
+		if strings.HasPrefix(l.src[l.pos:], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringPrefix 返回当前位置紧跟单引号的字符串前缀（如E、N），没有时返回空串
+func (l *Lexer) stringPrefix() string {
+	rest := l.src[l.pos:]
+	for _, prefix := range l.cfg.Strings.Prefixes {
+		if len(rest) > len(prefix) && strings.EqualFold(rest[:len(prefix)], prefix) && rest[len(prefix)] == '\'' {
+			return rest[:len(prefix)]
+		}
+	}
+	return ""
+}
+
+// skipString 跳过单引号字符串，backslash为true时反斜杠转义下一个字符
+func (l *Lexer) skipString(backslash bool) error {
+	return l.skipQuoted('\'', backslash)
+}
+
+// skipQuoted 跳过引号包裹的内容，连续两个结束引号表示转义
+func (l *Lexer) skipQuoted(quote rune, backslash bool) error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		l.pos += size
+		if backslash && r == '\\' && l.pos < len(l.src) {
+			_, size = utf8.DecodeRuneInString(l.src[l.pos:])
+			l.pos += size
+			continue
+		}
+		if r != quote {
+			continue
+		}
+		if l.peekRune(0) == quote {
+			l.pos += size
+			continue
+		}
+		return nil
+	}
+	if quote == '\'' {
+		return fmt.Errorf("unterminated string literal at offset %d", start)
+	}
+	return fmt.Errorf("unterminated quoted identifier at offset %d", start)
+}
+
+// dollarTag 返回当前位置的美元符号引用标记（如$$或$body$），不是标记时返回空串
+func (l *Lexer) dollarTag() string {
+	rest := l.src[l.pos:]
+	end := 1
+	for end < len(rest) {
+		r, size := utf8.DecodeRuneInString(rest[end:])
+		if r == '$' {
+			return rest[:end+1]
+		}
+		if !isIdentStart(r) && !(end > 1 && isDigit(r)) {
+			return ""
+		}
+		end += size
+	}
+	return ""
+}
+
+// skipDollarQuoted 跳过美元符号引用的字符串，直到相同的结束标记
+func (l *Lexer) skipDollarQuoted() error {
+	start := l.pos
+	tag := l.dollarTag()
+	idx := strings.Index(l.src[l.pos+len(tag):], tag)
+	if idx < 0 {
+		return fmt.Errorf("unterminated dollar-quoted string at offset %d", start)
+	}
+	l.pos += len(tag) + idx + len(tag)
+	return nil
+}
+
+// skipPlaceholder 跳过当前位置的占位符：?或?NNN、$NNN或$name、:name、@name或@@name；不是占位符时返回false
+func (l *Lexer) skipPlaceholder() bool {
+	r, next := l.peekRune(0), l.peekRune(1)
+	switch {
+	case r == '?':
+		l.pos++
+		l.skipWhile(isDigit)
+	case r == '$' && (isDigit(next) || isIdentStart(next)):
+		l.pos++
+		l.skipWhile(isIdentPart)
+	case (r == ':' || r == '@') && isIdentStart(next):
+		l.pos++
+		l.skipWhile(isIdentPart)
+	case r == '@' && next == '@' && isIdentStart(l.peekRune(2)):
+		l.pos += 2
+		l.skipWhile(isIdentPart)
+	default:
+		return false
+	}
+	return true
+}
+
+// skipNumber 跳过数字字面量
+func (l *Lexer) skipNumber() {
+	if l.src[l.pos] == '0' && (l.peekRune(1) == 'x' || l.peekRune(1) == 'X') {
+		l.pos += 2
+		l.skipWhile(isHexDigit)
+		return
+	}
+	l.skipWhile(isDigit)
+	if l.peekRune(0) == '.' && l.peekRune(1) != '.' {
+		l.pos++
+		l.skipWhile(isDigit)
+	}
+	if r := l.peekRune(0); r == 'e' || r == 'E' {
+		next := l.peekRune(1)
+		if isDigit(next) || ((next == '+' || next == '-') && isDigit(l.peekRune(2))) {
+			l.pos += 2
+			l.skipWhile(isDigit)
+		}
+	}
+}
+
+// skipOperator 跳过运算符，方言运算符优先于通用运算符
+func (l *Lexer) skipOperator() {
+	rest := l.src[l.pos:]
+	for _, op := range l.cfg.Operators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return
+		}
+	}
+	for _, op := range multiCharOperators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return
+		}
+	}
+	_, size := utf8.DecodeRuneInString(rest)
+	l.pos += size
+}
+
+// closingQuote 返回与开引号配对的结束引号，成对的括号返回其右括号
+func closingQuote(open rune) rune {
+	switch open {
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	case '<':
+		return '>'
+	}
+	return open
+}
+
+// isQQuote 当前位置是否为 q'X...X' 或 nq'X...X' 字符串
+func (l *Lexer) isQQuote() bool {
+	if !l.cfg.Strings.QQuotes {
+		return false
+	}
+	rest := l.src[l.pos:]
+	if len(rest) > 0 && (rest[0] == 'n' || rest[0] == 'N') {
+		rest = rest[1:]
+	}
+	if len(rest) < 3 || (rest[0] != 'q' && rest[0] != 'Q') || rest[1] != '\'' {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(rest[2:])
+	return r != utf8.RuneError && !unicode.IsSpace(r)
+}
+
+// skipQQuote 跳过 q'X...X' 字符串，直到结束定界符后紧跟单引号
+func (l *Lexer) skipQQuote() error {
+	start := l.pos
+	l.pos = strings.IndexByte(l.src[start:], '\'') + start + 1
+	open, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	closing := string(closingQuote(open)) + "'"
+	idx := strings.Index(l.src[l.pos:], closing)
+	if idx < 0 {
+		return fmt.Errorf("unterminated string literal at offset %d", start)
+	}
+	l.pos += idx + len(closing)
+	return nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isHexDigit(r rune) bool {
+	return isDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r != utf8.RuneError && unicode.IsLetter(r))
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || isDigit(r) || r == '$'
+}
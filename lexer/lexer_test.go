@@ -0,0 +1,279 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{
+			name:  "Keywords and identifiers",
+			input: "select id from users",
+			expected: []Token{
+				{Type: Keyword, Value: "select", Start: 0, End: 6},
+				{Type: Whitespace, Value: " ", Start: 6, End: 7},
+				{Type: Identifier, Value: "id", Start: 7, End: 9},
+				{Type: Whitespace, Value: " ", Start: 9, End: 10},
+				{Type: Keyword, Value: "from", Start: 10, End: 14},
+				{Type: Whitespace, Value: " ", Start: 14, End: 15},
+				{Type: Identifier, Value: "users", Start: 15, End: 20},
+			},
+		},
+		{
+			name:  "String with keywords and escaped quote",
+			input: "'select from where''s'",
+			expected: []Token{
+				{Type: String, Value: "'select from where''s'", Start: 0, End: 22},
+			},
+		},
+		{
+			name:  "Quoted identifiers",
+			input: "\"order\".`from`",
+			expected: []Token{
+				{Type: QuotedIdentifier, Value: "\"order\"", Start: 0, End: 7},
+				{Type: Punctuation, Value: ".", Start: 7, End: 8},
+				{Type: QuotedIdentifier, Value: "`from`", Start: 8, End: 14},
+			},
+		},
+		{
+			name:  "Comments",
+			input: "-- select\n/* from */",
+			expected: []Token{
+				{Type: Comment, Value: "-- select", Start: 0, End: 9},
+				{Type: Whitespace, Value: "\n", Start: 9, End: 10},
+				{Type: Comment, Value: "/* from */", Start: 10, End: 20},
+			},
+		},
+		{
+			name:  "Numbers and operators",
+			input: "1.5e3>=.5<>0x1F",
+			expected: []Token{
+				{Type: Number, Value: "1.5e3", Start: 0, End: 5},
+				{Type: Operator, Value: ">=", Start: 5, End: 7},
+				{Type: Number, Value: ".5", Start: 7, End: 9},
+				{Type: Operator, Value: "<>", Start: 9, End: 11},
+				{Type: Number, Value: "0x1F", Start: 11, End: 15},
+			},
+		},
+		{
+			name:  "Placeholders",
+			input: "? $1 :name",
+			expected: []Token{
+				{Type: Placeholder, Value: "?", Start: 0, End: 1},
+				{Type: Whitespace, Value: " ", Start: 1, End: 2},
+				{Type: Placeholder, Value: "$1", Start: 2, End: 4},
+				{Type: Whitespace, Value: " ", Start: 4, End: 5},
+				{Type: Placeholder, Value: ":name", Start: 5, End: 10},
+			},
+		},
+		{
+			name:  "Dollar-quoted strings",
+			input: "$$a;b$$ $fn$ 'x' $fn$",
+			expected: []Token{
+				{Type: String, Value: "$$a;b$$", Start: 0, End: 7},
+				{Type: Whitespace, Value: " ", Start: 7, End: 8},
+				{Type: String, Value: "$fn$ 'x' $fn$", Start: 8, End: 21},
+			},
+		},
+		{
+			name:  "Punctuation",
+			input: "f(a,b);",
+			expected: []Token{
+				{Type: Identifier, Value: "f", Start: 0, End: 1},
+				{Type: Punctuation, Value: "(", Start: 1, End: 2},
+				{Type: Identifier, Value: "a", Start: 2, End: 3},
+				{Type: Punctuation, Value: ",", Start: 3, End: 4},
+				{Type: Identifier, Value: "b", Start: 4, End: 5},
+				{Type: Punctuation, Value: ")", Start: 5, End: 6},
+				{Type: Punctuation, Value: ";", Start: 6, End: 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Tokenize(tt.input)
+			if err != nil {
+				t.Fatalf("Tokenize failed: %v", err)
+			}
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("Expected %d tokens, got %d: %v", len(tt.expected), len(tokens), tokens)
+			}
+			for i, expected := range tt.expected {
+				if tokens[i] != expected {
+					t.Errorf("Token %d mismatch, expected %+v, got %+v", i, expected, tokens[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Unterminated string", input: "select 'abc"},
+		{name: "Unterminated quoted identifier", input: "select \"abc"},
+		{name: "Unterminated block comment", input: "select /* abc"},
+		{name: "Unterminated dollar-quoted string", input: "select $body$ abc $$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Tokenize(tt.input); err == nil {
+				t.Errorf("Expected error to occur, but no error happened")
+			}
+		})
+	}
+}
+
+func TestTokenizeWithConfig(t *testing.T) {
+	cfg := &Config{
+		Keywords:         []string{"pragma"},
+		IdentifierQuotes: "[",
+		Strings:          StringRules{Prefixes: []string{"N", "E"}},
+		LineComments:     []string{"--", "#"},
+		Placeholders:     "?@",
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{
+			name:  "Dialect keywords",
+			input: "PRAGMA x",
+			expected: []Token{
+				{Type: Keyword, Value: "PRAGMA", Start: 0, End: 6},
+				{Type: Whitespace, Value: " ", Start: 6, End: 7},
+				{Type: Identifier, Value: "x", Start: 7, End: 8},
+			},
+		},
+		{
+			name:  "Bracketed identifiers",
+			input: "[a]]b].c",
+			expected: []Token{
+				{Type: QuotedIdentifier, Value: "[a]]b]", Start: 0, End: 6},
+				{Type: Punctuation, Value: ".", Start: 6, End: 7},
+				{Type: Identifier, Value: "c", Start: 7, End: 8},
+			},
+		},
+		{
+			name:  "Prefixed strings",
+			input: "N'a' e'b\\'c'",
+			expected: []Token{
+				{Type: String, Value: "N'a'", Start: 0, End: 4},
+				{Type: Whitespace, Value: " ", Start: 4, End: 5},
+				{Type: String, Value: "e'b\\'c'", Start: 5, End: 12},
+			},
+		},
+		{
+			name:  "Hash comments",
+			input: "# a\n-- b",
+			expected: []Token{
+				{Type: Comment, Value: "# a", Start: 0, End: 3},
+				{Type: Whitespace, Value: "\n", Start: 3, End: 4},
+				{Type: Comment, Value: "-- b", Start: 4, End: 8},
+			},
+		},
+		{
+			name:  "Dialect placeholders",
+			input: "?1 @id $$",
+			expected: []Token{
+				{Type: Placeholder, Value: "?1", Start: 0, End: 2},
+				{Type: Whitespace, Value: " ", Start: 2, End: 3},
+				{Type: Placeholder, Value: "@id", Start: 3, End: 6},
+				{Type: Whitespace, Value: " ", Start: 6, End: 7},
+				{Type: Operator, Value: "$", Start: 7, End: 8},
+				{Type: Operator, Value: "$", Start: 8, End: 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := TokenizeWithConfig(tt.input, cfg)
+			if err != nil {
+				t.Fatalf("Tokenize failed: %v", err)
+			}
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("Expected %d tokens, got %d: %v", len(tt.expected), len(tokens), tokens)
+			}
+			for i, expected := range tt.expected {
+				if tokens[i] != expected {
+					t.Errorf("Token %d mismatch, expected %+v, got %+v", i, expected, tokens[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizeBackslashEscapes(t *testing.T) {
+	cfg := DefaultConfig()
+	if _, err := TokenizeWithConfig(`select 'it\'s'`, cfg); err == nil {
+		t.Errorf("Expected unterminated string without backslash escapes")
+	}
+	cfg.Strings.BackslashEscapes = true
+	tokens, err := TokenizeWithConfig(`'it\'s'`, cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Type != String {
+		t.Errorf("Expected a single string token, got %v", tokens)
+	}
+	cfg.Strings.DoubleQuotes = true
+	tokens, err = TokenizeWithConfig(`"say \"hi\""`, cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Type != String {
+		t.Errorf("Expected a double-quoted string token, got %v", tokens)
+	}
+}
+
+func TestTokenizeQQuotes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Strings.QQuotes = true
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "q'[it's]'", expected: "q'[it's]'"},
+		{input: "Q'{a]'b}'", expected: "Q'{a]'b}'"},
+		{input: "nq'<x>'", expected: "nq'<x>'"},
+		{input: "q'!don't!'", expected: "q'!don't!'"},
+	}
+	for _, tt := range tests {
+		tokens, err := TokenizeWithConfig(tt.input+" x", cfg)
+		if err != nil {
+			t.Fatalf("Tokenize %q failed: %v", tt.input, err)
+		}
+		if tokens[0].Type != String || tokens[0].Value != tt.expected {
+			t.Errorf("Expected string %q, got %+v", tt.expected, tokens[0])
+		}
+	}
+	if _, err := TokenizeWithConfig("q'[abc'", cfg); err == nil {
+		t.Errorf("Expected unterminated q-quoted string")
+	}
+}
+
+func TestTokenizeSlashSeparator(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Scripts.BatchSeparators = []string{"/"}
+	tokens, err := TokenizeWithConfig("SELECT total\n/ 2\nFROM t\n/\n", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	// 行首的除号后面还有内容，不是分隔符
+	if tok := tokens[4]; tok.Type != Operator || tok.Value != "/" {
+		t.Errorf("Expected division operator, got %+v", tok)
+	}
+	if tok := tokens[len(tokens)-2]; tok.Type != Separator || tok.Value != "/" {
+		t.Errorf("Expected slash separator, got %+v", tok)
+	}
+}
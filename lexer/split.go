@@ -0,0 +1,210 @@
+package lexer
+
+import (
+	"strings"
+)
+
+// Statement is one statement of a script as produced by Split
+type Statement struct {
+	Tokens     []Token // tokens of the statement, without the terminator
+	Terminated bool    // whether the statement was followed by a terminator
+	Terminator string  // the terminator in effect: a semicolon or the delimiter of a DELIMITER directive
+	Trailing   []Token // comments on the same line after the terminator
+	Separator  string  // batch separator line that follows the statement, such as GO
+	Block      bool    // whether the statement is a procedural block or routine, whose final semicolon is part of its syntax
+}
+
+// Split divides a token stream into statements at top-level semicolons.
+// Semicolons inside strings, quoted identifiers, comments and dollar-quoted
+// bodies are already part of those tokens and never split a statement.
+// A Directive token forms a statement of its own; while a DELIMITER
+// directive is in effect only Delimiter tokens end statements. A Separator
+// token ends the pending statement, or is attached to the previous one when
+// only whitespace separates them.
+func Split(tokens []Token) []Statement {
+	return SplitWithConfig(tokens, DefaultConfig())
+}
+
+// SplitWithConfig is like Split but follows the script rules of a dialect;
+// with Blocks or Routines set, semicolons inside a procedural block or a
+// routine body do not end it
+func SplitWithConfig(tokens []Token, cfg *Config) []Statement {
+	var stmts []Statement
+	start := 0
+	terminator := ";"
+	block := blockTracker{blocks: cfg.Scripts.Blocks, routines: cfg.Scripts.Routines}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		block.observe(tok)
+		if tok.Type == Directive {
+			if start < i {
+				stmts = append(stmts, Statement{Tokens: tokens[start:i], Terminator: terminator, Block: block.active})
+			}
+			stmts = append(stmts, Statement{Tokens: tokens[i : i+1]})
+			if terminator = directiveDelimiter(tok.Value); terminator == "" {
+				terminator = ";"
+			}
+			start = i + 1
+			block.reset()
+			continue
+		}
+		if tok.Type == Separator {
+			separator := strings.TrimSpace(tok.Value)
+			pending := Statement{Tokens: tokens[start:i], Terminator: terminator, Separator: separator, Block: block.active}
+			if n := len(stmts); n > 0 && stmts[n-1].Separator == "" && !stmts[n-1].IsDirective() && isWhitespace(pending.Tokens) {
+				stmts[n-1].Separator = separator
+			} else {
+				stmts = append(stmts, pending)
+			}
+			start = i + 1
+			block.reset()
+			continue
+		}
+		if tok.Type != Delimiter && (terminator != ";" || !tok.IsPunct(";") || block.open()) {
+			continue
+		}
+		stmt := Statement{Tokens: tokens[start:i], Terminated: true, Terminator: tok.Value, Block: block.active}
+		// 分号之后同一行的注释属于该语句
+		end := i + 1
+		for j := end; j < len(tokens); j++ {
+			tok := tokens[j]
+			if tok.Type == Comment {
+				end = j + 1
+			} else if tok.Type != Whitespace || strings.Contains(tok.Value, "\n") {
+				break
+			}
+		}
+		stmt.Trailing = tokens[i+1 : end]
+		stmts = append(stmts, stmt)
+		start = end
+		i = end - 1
+		block.reset()
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, Statement{Tokens: tokens[start:], Terminator: terminator, Block: block.active})
+	}
+	return stmts
+}
+
+// IsDirective reports whether the statement is a client directive such as DELIMITER $$
+func (s Statement) IsDirective() bool {
+	return len(s.Tokens) == 1 && s.Tokens[0].Type == Directive
+}
+
+// blockTracker 跟踪过程块和存储过程定义中BEGIN、CASE、IF、LOOP与END的嵌套
+type blockTracker struct {
+	blocks   bool   // 以DECLARE或BEGIN开头的语句是过程块，IF和LOOP以END IF、END LOOP结束
+	routines bool   // CREATE PROCEDURE等定义存储过程的语句带有过程体
+	started  bool   // 当前语句已出现非注释词法单元
+	create   bool   // 当前语句以CREATE或ALTER开头，尚未确定定义的对象
+	active   bool   // 当前语句是过程块或存储过程定义
+	header   bool   // 已出现PROCEDURE、FUNCTION等单词，尚未遇到其后的IS或AS
+	levels   []bool // 未闭合的块，true表示声明部分之后的BEGIN属于该块，不另开一层
+	joined   bool   // 上一个BEGIN并入了声明部分所在的块
+	prev     string // 上一个单词的大写形式
+}
+
+// routineKinds 定义存储过程的语句中CREATE之后的对象类型
+var routineKinds = map[string]bool{
+	"PROCEDURE": true, "PROC": true, "FUNCTION": true, "PACKAGE": true, "TRIGGER": true,
+}
+
+// routineModifiers CREATE与对象类型之间可以出现的单词
+var routineModifiers = map[string]bool{
+	"OR": true, "REPLACE": true, "ALTER": true, "EDITIONABLE": true, "NONEDITIONABLE": true,
+}
+
+// observe 记录当前语句中的一个词法单元
+func (b *blockTracker) observe(tok Token) {
+	if !b.blocks && !b.routines || tok.IsTrivia() {
+		return
+	}
+	word := ""
+	if tok.Type == Keyword || tok.Type == Identifier {
+		word = tok.Upper()
+	}
+	if !b.started {
+		b.started = true
+		switch {
+		case b.blocks && (word == "DECLARE" || word == "BEGIN"):
+			b.active = true
+		case b.routines && (word == "CREATE" || word == "ALTER"):
+			b.create = true
+		}
+	} else if b.create {
+		b.create = routineModifiers[word]
+		b.active = routineKinds[word]
+	}
+	if !b.active {
+		return
+	}
+	n := len(b.levels)
+	switch {
+	case tok.IsPunct(";"):
+		b.header = false
+	case routineKinds[word]:
+		b.header = true
+	case b.header && (word == "IS" || word == "AS"):
+		// 存储过程的声明部分，随后的BEGIN与之共用一个END
+		b.header = false
+		b.levels = append(b.levels, true)
+	case b.blocks && word == "DECLARE":
+		b.header = false
+		b.levels = append(b.levels, true)
+	case word == "BEGIN":
+		b.header = false
+		b.joined = n > 0 && b.levels[n-1]
+		if b.joined {
+			b.levels[n-1] = false
+		} else {
+			b.levels = append(b.levels, false)
+		}
+	case (word == "TRAN" || word == "TRANSACTION") && b.prev == "BEGIN":
+		// BEGIN TRAN开启事务而不是块
+		if b.joined {
+			b.levels[n-1] = true
+		} else {
+			b.levels = b.levels[:n-1]
+		}
+	case word == "CASE" || b.blocks && (word == "IF" || word == "LOOP"):
+		// END IF、END LOOP、END CASE中的第二个单词不开启新块
+		if b.prev != "END" {
+			b.levels = append(b.levels, false)
+		}
+	case word == "END":
+		if n > 0 {
+			b.levels = b.levels[:n-1]
+		}
+	}
+	b.prev = word
+}
+
+// open 分号是否位于尚未结束的过程块内
+func (b *blockTracker) open() bool {
+	return b.active && (b.header || len(b.levels) > 0)
+}
+
+// reset 在语句结束后重新开始跟踪
+func (b *blockTracker) reset() {
+	*b = blockTracker{blocks: b.blocks, routines: b.routines}
+}
+
+// isWhitespace 词法单元是否全部为空白
+func isWhitespace(tokens []Token) bool {
+	for _, tok := range tokens {
+		if tok.Type != Whitespace {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether the statement has no tokens other than whitespace and comments
+func (s Statement) IsEmpty() bool {
+	for _, tok := range s.Tokens {
+		if !tok.IsTrivia() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		statements []string
+		terminated []bool
+		trailing   []string
+	}{
+		{
+			name:       "Two statements",
+			input:      "select 1; select 2",
+			statements: []string{"select 1", " select 2"},
+			terminated: []bool{true, false},
+			trailing:   []string{"", ""},
+		},
+		{
+			name:       "Semicolons inside strings, comments and dollar quotes",
+			input:      "select ';' /* ; */, $$;$$;",
+			statements: []string{"select ';' /* ; */, $$;$$"},
+			terminated: []bool{true},
+			trailing:   []string{""},
+		},
+		{
+			name:       "Comment after semicolon on the same line",
+			input:      "select 1; -- one\n-- two\nselect 2;",
+			statements: []string{"select 1", "\n-- two\nselect 2"},
+			terminated: []bool{true, true},
+			trailing:   []string{" -- one", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Tokenize(tt.input)
+			if err != nil {
+				t.Fatalf("Tokenize failed: %v", err)
+			}
+			stmts := Split(tokens)
+			if len(stmts) != len(tt.statements) {
+				t.Fatalf("Expected %d statements, got %d", len(tt.statements), len(stmts))
+			}
+			for i, stmt := range stmts {
+				if got := joinValues(stmt.Tokens); got != tt.statements[i] {
+					t.Errorf("Statement %d: expected %q, got %q", i, tt.statements[i], got)
+				}
+				if stmt.Terminated != tt.terminated[i] {
+					t.Errorf("Statement %d: expected terminated %v, got %v", i, tt.terminated[i], stmt.Terminated)
+				}
+				if got := joinValues(stmt.Trailing); got != tt.trailing[i] {
+					t.Errorf("Statement %d: expected trailing %q, got %q", i, tt.trailing[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitDelimiterDirective(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Scripts.DelimiterDirective = true
+	tokens, err := TokenizeWithConfig("select 1;\nDELIMITER //\ncreate procedure p() begin select 2; end//\ndelimiter ;\nselect 3", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts := Split(tokens)
+
+	expected := []struct {
+		text       string
+		directive  bool
+		terminator string
+	}{
+		{"select 1", false, ";"},
+		{"DELIMITER //", true, ""},
+		{"create procedure p() begin select 2; end", false, "//"},
+		{"delimiter ;", true, ""},
+		{"select 3", false, ";"},
+	}
+	var got []Statement
+	for _, stmt := range stmts {
+		if !stmt.IsEmpty() {
+			got = append(got, stmt)
+		}
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d", len(expected), len(got))
+	}
+	for i, stmt := range got {
+		if text := strings.TrimSpace(joinValues(stmt.Tokens)); text != expected[i].text {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i].text, text)
+		}
+		if stmt.IsDirective() != expected[i].directive || stmt.Terminator != expected[i].terminator {
+			t.Errorf("Statement %d: expected directive %v terminator %q, got %v %q",
+				i, expected[i].directive, expected[i].terminator, stmt.IsDirective(), stmt.Terminator)
+		}
+	}
+}
+
+func TestSplitBatchSeparator(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Scripts.BatchSeparators = []string{"GO"}
+	cfg.Scripts.RepeatCount = true
+	tokens, err := TokenizeWithConfig("select 1\ngo\nselect 2;\nGO 3\nselect go from t", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	var got []Statement
+	for _, stmt := range Split(tokens) {
+		if !stmt.IsEmpty() {
+			got = append(got, stmt)
+		}
+	}
+
+	expected := []struct {
+		text      string
+		separator string
+	}{
+		{"select 1", "go"},
+		{"select 2", "GO 3"},
+		{"select go from t", ""},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d", len(expected), len(got))
+	}
+	for i, stmt := range got {
+		if text := strings.TrimSpace(joinValues(stmt.Tokens)); text != expected[i].text {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i].text, text)
+		}
+		if stmt.Separator != expected[i].separator {
+			t.Errorf("Statement %d: expected separator %q, got %q", i, expected[i].separator, stmt.Separator)
+		}
+	}
+}
+
+func TestSplitBlocks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Keywords = []string{"BEGIN", "DECLARE", "LOOP"}
+	cfg.Scripts.Blocks = true
+	src := "declare x int; begin if x > 0 then null; end if; loop exit; end loop; begin null; end; end;\n" +
+		"select case when a then 1 end from t;\nbegin null; end"
+	tokens, err := TokenizeWithConfig(src, cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	var got []string
+	for _, stmt := range SplitWithConfig(tokens, cfg) {
+		if !stmt.IsEmpty() {
+			got = append(got, strings.TrimSpace(joinValues(stmt.Tokens)))
+		}
+	}
+	expected := []string{
+		"declare x int; begin if x > 0 then null; end if; loop exit; end loop; begin null; end; end",
+		"select case when a then 1 end from t",
+		"begin null; end",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d: %q", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestSplitRoutines(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Keywords = []string{"BEGIN", "PROCEDURE", "TRAN"}
+	cfg.Scripts.BatchSeparators = []string{"GO"}
+	cfg.Scripts.Routines = true
+	src := "create procedure p as begin set nocount on; if @x > 0 begin select 1; end; end;\n" +
+		"create or alter procedure q as begin tran; update t set a = 1; commit;\ngo\n" +
+		"create table t (a int); select 2"
+	tokens, err := TokenizeWithConfig(src, cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	var got []string
+	for _, stmt := range SplitWithConfig(tokens, cfg) {
+		if !stmt.IsEmpty() {
+			got = append(got, strings.TrimSpace(joinValues(stmt.Tokens)))
+		}
+	}
+	expected := []string{
+		"create procedure p as begin set nocount on; if @x > 0 begin select 1; end; end",
+		"create or alter procedure q as begin tran; update t set a = 1; commit;",
+		"create table t (a int)",
+		"select 2",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d: %q", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestSplitPackages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Keywords = []string{"BEGIN", "LOOP", "PACKAGE", "PROCEDURE"}
+	cfg.Scripts.Blocks = true
+	cfg.Scripts.Routines = true
+	src := "create or replace package body pk as procedure x is begin if a then null; end if; end; " +
+		"function f return number is v number; begin return 1; end f; begin init; end pk;\n" +
+		"create package s is procedure x; end s;\n" +
+		"create trigger tr before insert on t for each row begin select a as b into :new.x from dual; end;\n" +
+		"alter procedure p compile; select 1 from dual"
+	tokens, err := TokenizeWithConfig(src, cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	var got []string
+	for _, stmt := range SplitWithConfig(tokens, cfg) {
+		if !stmt.IsEmpty() {
+			got = append(got, strings.TrimSpace(joinValues(stmt.Tokens)))
+		}
+	}
+	expected := []string{
+		"create or replace package body pk as procedure x is begin if a then null; end if; end; " +
+			"function f return number is v number; begin return 1; end f; begin init; end pk",
+		"create package s is procedure x; end s",
+		"create trigger tr before insert on t for each row begin select a as b into :new.x from dual; end",
+		"alter procedure p compile",
+		"select 1 from dual",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d: %q", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
+func joinValues(tokens []Token) string {
+	var result strings.Builder
+	for _, tok := range tokens {
+		result.WriteString(tok.Value)
+	}
+	return result.String()
+}
@@ -0,0 +1,97 @@
+package lexer
+
+import "strings"
+
+// TokenType identifies the lexical class of a token
+type TokenType int
+
+const (
+	// Whitespace is a run of spaces, tabs and newlines
+	Whitespace TokenType = iota
+	// Comment is a line comment (-- ...) or a block comment (/* ... */)
+	Comment
+	// Keyword is a reserved or non-reserved SQL keyword
+	Keyword
+	// Identifier is an unquoted name
+	Identifier
+	// QuotedIdentifier is a name wrapped in identifier quotes
+	QuotedIdentifier
+	// String is a string literal
+	String
+	// Number is a numeric literal
+	Number
+	// Operator is an arithmetic, comparison or other symbolic operator
+	Operator
+	// Punctuation is one of ( ) , ; . [ ]
+	Punctuation
+	// Placeholder is a bind parameter such as ?, $1 or :name
+	Placeholder
+	// Directive is a client directive line such as DELIMITER $$
+	Directive
+	// Delimiter is a statement terminator other than the semicolon, such as
+	// the one set by a DELIMITER directive
+	Delimiter
+	// Separator is a batch separator line such as GO
+	Separator
+)
+
+var tokenTypeNames = map[TokenType]string{
+	Whitespace:       "Whitespace",
+	Comment:          "Comment",
+	Keyword:          "Keyword",
+	Identifier:       "Identifier",
+	QuotedIdentifier: "QuotedIdentifier",
+	String:           "String",
+	Number:           "Number",
+	Operator:         "Operator",
+	Punctuation:      "Punctuation",
+	Placeholder:      "Placeholder",
+	Directive:        "Directive",
+	Delimiter:        "Delimiter",
+	Separator:        "Separator",
+}
+
+// String returns the name of the token type
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Token is a single lexical unit of SQL text
+type Token struct {
+	Type  TokenType
+	Value string // 原始文本
+	Start int    // 起始字节偏移
+	End   int    // 结束字节偏移（不含）
+}
+
+// Upper returns the token value in upper case
+func (t Token) Upper() string {
+	return strings.ToUpper(t.Value)
+}
+
+// IsKeyword reports whether the token is one of the given keywords (case-insensitive)
+func (t Token) IsKeyword(words ...string) bool {
+	if t.Type != Keyword {
+		return false
+	}
+	upper := t.Upper()
+	for _, word := range words {
+		if upper == word {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPunct reports whether the token is the given punctuation
+func (t Token) IsPunct(p string) bool {
+	return t.Type == Punctuation && t.Value == p
+}
+
+// IsTrivia reports whether the token is whitespace or a comment
+func (t Token) IsTrivia() bool {
+	return t.Type == Whitespace || t.Type == Comment
+}
@@ -0,0 +1,549 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// tableConstraintStarts 表定义中以这些词开头的元素是表级约束而不是列定义
+var tableConstraintStarts = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "FOREIGN": true, "UNIQUE": true, "CHECK": true,
+	"EXCLUDE": true, "LIKE": true, "KEY": true, "INDEX": true, "FULLTEXT": true, "SPATIAL": true,
+}
+
+// parseCreate 解析CREATE语句
+func (p *Parser) parseCreate() (ast.Statement, error) {
+	pos := p.peek().Start
+	create, err := p.expectKeyword("CREATE")
+	if err != nil {
+		return nil, err
+	}
+	orReplace, _ := p.acceptKeyword("OR", "REPLACE")
+	temporary := p.acceptTemporary()
+	switch {
+	case p.isKeyword("VIEW") || p.isKeywordSeq("MATERIALIZED", "VIEW"):
+		return p.parseCreateView(pos, orReplace, temporary)
+	case orReplace != "":
+		// OR REPLACE 只用于视图
+	case p.isKeyword("TABLE"):
+		return p.parseCreateTable(pos, temporary)
+	case p.isKeyword("SEQUENCE"):
+		return p.parseSequence(pos, create, temporary)
+	case temporary != "":
+		// 索引和模式没有临时形式
+	case p.isKeyword("INDEX", "UNIQUE", "FULLTEXT", "SPATIAL"):
+		return p.parseCreateIndex(pos)
+	case p.isKeyword("SCHEMA"):
+		return p.parseCreateSchema(pos)
+	case p.isKeywordSeq("VIRTUAL", "TABLE"):
+		return p.parseCreateVirtualTable(pos)
+	}
+	return nil, p.errorf("unsupported statement %q", "CREATE "+p.peek().Value)
+}
+
+// acceptTemporary 解析可选的 [GLOBAL|LOCAL] TEMPORARY、TEMP 或 UNLOGGED
+func (p *Parser) acceptTemporary() ast.Keyword {
+	for _, words := range [][]string{
+		{"GLOBAL", "TEMPORARY"}, {"LOCAL", "TEMPORARY"}, {"GLOBAL", "TEMP"}, {"LOCAL", "TEMP"},
+		{"TEMPORARY"}, {"TEMP"}, {"UNLOGGED"},
+	} {
+		if kw, ok := p.acceptKeyword(words...); ok {
+			return kw
+		}
+	}
+	return ""
+}
+
+// parseCreateTable 解析CREATE TABLE语句的TABLE关键字之后的部分
+func (p *Parser) parseCreateTable(pos int, temporary ast.Keyword) (*ast.CreateTableStmt, error) {
+	if _, err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+	stmt := &ast.CreateTableStmt{Pos: pos, Temporary: temporary, Rparen: -1}
+	stmt.IfNotExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+
+	// 列定义和表级约束
+	if p.acceptPunct("(") {
+		for {
+			if tableConstraintStarts[p.peek().Upper()] {
+				constraint := &ast.TableConstraint{Pos: p.peek().Start}
+				raw := p.parseRawUntil(func() bool { return p.peek().IsPunct(",") })
+				if raw == nil {
+					return nil, p.unexpected()
+				}
+				constraint.Text = raw.Text
+				stmt.Constraints = append(stmt.Constraints, constraint)
+			} else {
+				column, err := p.parseColumnDef()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Columns = append(stmt.Columns, column)
+			}
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+		stmt.Rparen = p.peek().Start
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	// 表选项、分区和AS查询
+	stmt.Options = p.parseRawUntil(func() bool {
+		return p.isKeywordSeq("PARTITION", "BY") || p.isKeyword("AS")
+	})
+	if _, ok := p.acceptKeyword("PARTITION", "BY"); ok {
+		if stmt.PartitionBy = p.parseRawUntil(func() bool { return p.isKeyword("AS") }); stmt.PartitionBy == nil {
+			return nil, p.errorf("expected partition method")
+		}
+	}
+	if as, ok := p.acceptKeyword("AS"); ok {
+		stmt.As = as
+		if stmt.Query, err = p.parseQuery(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.Rparen < 0 && stmt.Query == nil && stmt.Options == nil {
+		return nil, p.errorf("expected %q", "(")
+	}
+	return stmt, nil
+}
+
+// parseColumnDef 解析列定义：列名、可选的类型以及原样保留的列约束
+func (p *Parser) parseColumnDef() (*ast.ColumnDef, error) {
+	name, err := p.parseName(false)
+	if err != nil {
+		return nil, err
+	}
+	column := &ast.ColumnDef{Name: name}
+	if p.isNameToken(p.peek()) {
+		if column.Type, err = p.parseDataType(); err != nil {
+			return nil, err
+		}
+	}
+	if raw := p.parseRawUntil(func() bool { return p.peek().IsPunct(",") }); raw != nil {
+		column.Constraints = raw.Text
+	}
+	return column, nil
+}
+
+// parseRawUntil 原样收集词法单元，直到括号外stop返回true、遇到未匹配的右括号、分号或输入结束；
+// 没有收集到任何词法单元时返回nil
+func (p *Parser) parseRawUntil(stop func() bool) *ast.RawExpr {
+	start := p.pos
+	depth := 0
+	for !p.atEnd() {
+		tok := p.peek()
+		if depth == 0 && (stop() || tok.IsPunct(")") || tok.IsPunct(";")) {
+			break
+		}
+		if tok.IsPunct("(") {
+			depth++
+		} else if tok.IsPunct(")") {
+			depth--
+		}
+		p.next()
+	}
+	if p.pos == start {
+		return nil
+	}
+	return p.rawExpr(start, p.pos)
+}
+
+// parseAlter 解析ALTER语句
+func (p *Parser) parseAlter() (ast.Statement, error) {
+	pos := p.peek().Start
+	alter, err := p.expectKeyword("ALTER")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isKeyword("TABLE"):
+		return p.parseAlterTable(pos)
+	case p.isKeyword("SEQUENCE"):
+		return p.parseSequence(pos, alter, "")
+	}
+	return nil, p.errorf("unsupported statement %q", "ALTER "+p.peek().Value)
+}
+
+// parseAlterTable 解析ALTER TABLE语句的TABLE关键字之后的部分
+func (p *Parser) parseAlterTable(pos int) (*ast.AlterTableStmt, error) {
+	if _, err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+	stmt := &ast.AlterTableStmt{Pos: pos}
+	stmt.IfExists, _ = p.acceptKeyword("IF", "EXISTS")
+	stmt.Only, _ = p.acceptKeyword("ONLY")
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+	for {
+		action, err := p.parseAlterAction()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Actions = append(stmt.Actions, action)
+		if !p.acceptPunct(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// parseAlterAction 解析ALTER TABLE的单个操作，识别出的关键字保存在Action中，
+// ADD/MODIFY的列定义单独解析，其余部分原样保留
+func (p *Parser) parseAlterAction() (*ast.AlterAction, error) {
+	action := &ast.AlterAction{Pos: p.peek().Start}
+	var words []string
+	take := func(seq ...string) bool {
+		kw, ok := p.acceptKeyword(seq...)
+		if ok {
+			words = append(words, string(kw))
+		}
+		return ok
+	}
+
+	column := false
+	switch {
+	case take("ADD"):
+		if take("CONSTRAINT") {
+			break
+		}
+		column = take("COLUMN")
+		take("IF", "NOT", "EXISTS")
+		column = column || !tableConstraintStarts[p.peek().Upper()]
+	case take("MODIFY"):
+		take("COLUMN")
+		column = true
+	case take("DROP"):
+		_ = take("COLUMN") || take("CONSTRAINT")
+		take("IF", "EXISTS")
+	case take("ALTER"), take("CHANGE"):
+		take("COLUMN")
+	case take("RENAME"):
+		_ = take("COLUMN") || take("CONSTRAINT") || take("TO")
+	}
+	action.Action = ast.Keyword(strings.Join(words, " "))
+
+	if column {
+		var err error
+		if action.Column, err = p.parseColumnDef(); err != nil {
+			return nil, err
+		}
+		return action, nil
+	}
+	raw := p.parseRawUntil(func() bool { return p.peek().IsPunct(",") })
+	if raw == nil {
+		return nil, p.unexpected()
+	}
+	action.Text = raw.Text
+	return action, nil
+}
+
+// parseCreateIndex 解析CREATE INDEX语句的INDEX及其修饰词之后的部分
+func (p *Parser) parseCreateIndex(pos int) (*ast.CreateIndexStmt, error) {
+	stmt := &ast.CreateIndexStmt{Pos: pos}
+	if p.isKeyword("UNIQUE", "FULLTEXT", "SPATIAL") {
+		stmt.Unique = ast.Keyword(p.next().Value)
+	}
+	if _, err := p.expectKeyword("INDEX"); err != nil {
+		return nil, err
+	}
+	stmt.Concurrently, _ = p.acceptKeyword("CONCURRENTLY")
+	stmt.IfNotExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	var err error
+	if !p.isKeyword("ON") {
+		if stmt.Name, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+	}
+	// MySQL允许索引类型写在ON之前
+	if err := p.parseIndexMethod(stmt); err != nil {
+		return nil, err
+	}
+	if stmt.On, err = p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+	if stmt.Table, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+	if stmt.Using == "" {
+		if err := p.parseIndexMethod(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	if stmt.Columns, err = p.parseRawUntilClose(); err != nil {
+		return nil, err
+	}
+	if _, ok := p.acceptKeyword("INCLUDE"); ok {
+		if stmt.Include, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+	stmt.Options = p.parseRawUntil(func() bool { return p.isKeyword("WHERE") })
+	if _, ok := p.acceptKeyword("WHERE"); ok {
+		if stmt.Where, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseIndexMethod 解析可选的 USING method
+func (p *Parser) parseIndexMethod(stmt *ast.CreateIndexStmt) error {
+	using, ok := p.acceptKeyword("USING")
+	if !ok {
+		return nil
+	}
+	method, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+	stmt.Using, stmt.Method = using, method
+	return nil
+}
+
+// parseCreateView 解析CREATE [MATERIALIZED] VIEW语句的VIEW之前修饰词之后的部分
+func (p *Parser) parseCreateView(pos int, orReplace, temporary ast.Keyword) (*ast.CreateViewStmt, error) {
+	stmt := &ast.CreateViewStmt{Pos: pos, OrReplace: orReplace, Temporary: temporary}
+	stmt.Materialized, _ = p.acceptKeyword("MATERIALIZED")
+	if _, err := p.expectKeyword("VIEW"); err != nil {
+		return nil, err
+	}
+	stmt.IfNotExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+	if p.peek().IsPunct("(") {
+		if stmt.Columns, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.As, err = p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	if stmt.Query, err = p.parseQuery(); err != nil {
+		return nil, err
+	}
+	stmt.Options = p.parseRawUntil(func() bool { return false })
+	return stmt, nil
+}
+
+// sequenceOptions 序列定义中每个选项的起始词
+var sequenceOptions = map[string]bool{
+	"AS": true, "INCREMENT": true, "START": true, "RESTART": true, "MINVALUE": true, "MAXVALUE": true,
+	"NO": true, "NOMINVALUE": true, "NOMAXVALUE": true, "CACHE": true, "NOCACHE": true,
+	"CYCLE": true, "NOCYCLE": true, "ORDER": true, "NOORDER": true, "OWNED": true, "OWNER": true,
+	"SET": true, "RENAME": true,
+}
+
+// parseSequence 解析CREATE SEQUENCE或ALTER SEQUENCE语句的SEQUENCE之后的部分，
+// 选项按起始词拆分，各自原样保留
+func (p *Parser) parseSequence(pos int, command, temporary ast.Keyword) (*ast.SequenceStmt, error) {
+	stmt := &ast.SequenceStmt{Pos: pos, Command: command, Temporary: temporary}
+	if _, err := p.expectKeyword("SEQUENCE"); err != nil {
+		return nil, err
+	}
+	if command.Is("CREATE") {
+		stmt.IfExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	} else {
+		stmt.IfExists, _ = p.acceptKeyword("IF", "EXISTS")
+	}
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+
+	start := p.pos
+	for ; !p.atEnd() && !p.peek().IsPunct(";"); p.next() {
+		// NO MINVALUE、NO CYCLE 等作为一个选项
+		if p.pos > start && sequenceOptions[p.peek().Upper()] && p.tokens[p.pos-1].Upper() != "NO" {
+			stmt.Options = append(stmt.Options, p.rawExpr(start, p.pos))
+			start = p.pos
+		}
+	}
+	if p.pos > start {
+		stmt.Options = append(stmt.Options, p.rawExpr(start, p.pos))
+	}
+	return stmt, nil
+}
+
+// parseCreateSchema 解析CREATE SCHEMA语句的SCHEMA之前部分之后的内容
+func (p *Parser) parseCreateSchema(pos int) (*ast.CreateSchemaStmt, error) {
+	if _, err := p.expectKeyword("SCHEMA"); err != nil {
+		return nil, err
+	}
+	stmt := &ast.CreateSchemaStmt{Pos: pos}
+	stmt.IfNotExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	if p.peek().Upper() != "AUTHORIZATION" {
+		var err error
+		if stmt.Name, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+	}
+	stmt.Options = p.parseRawUntil(func() bool { return false })
+	if stmt.Name == nil && stmt.Options == nil {
+		return nil, p.errorf("expected schema name")
+	}
+	return stmt, nil
+}
+
+// parseCreateVirtualTable 解析CREATE VIRTUAL TABLE语句的VIRTUAL关键字之后的部分
+func (p *Parser) parseCreateVirtualTable(pos int) (*ast.CreateVirtualTableStmt, error) {
+	p.pos += 2
+	stmt := &ast.CreateVirtualTableStmt{Pos: pos, Rparen: -1}
+	stmt.IfNotExists, _ = p.acceptKeyword("IF", "NOT", "EXISTS")
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+	if stmt.Using, err = p.expectKeyword("USING"); err != nil {
+		return nil, err
+	}
+	if stmt.Module, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if p.acceptPunct("(") {
+		for !p.peek().IsPunct(")") {
+			arg := p.parseRawUntil(func() bool { return p.peek().IsPunct(",") })
+			if arg == nil {
+				return nil, p.unexpected()
+			}
+			stmt.Args = append(stmt.Args, arg)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+		stmt.Rparen = p.peek().Start
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// ParsePragma parses SQLite's PRAGMA [schema.]name [= value | (value)];
+// dialects register it as a StatementParser
+func (p *Parser) ParsePragma() (ast.Statement, error) {
+	tok := p.next()
+	stmt := &ast.PragmaStmt{Pos: tok.Start, Pragma: ast.Keyword(tok.Value)}
+	var err error
+	if stmt.Name, err = p.parseName(false); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isOperator("="):
+		p.next()
+		stmt.Eq = true
+		if stmt.Value = p.parseRawUntil(func() bool { return false }); stmt.Value == nil {
+			return nil, p.errorf("expected pragma value")
+		}
+	case p.acceptPunct("("):
+		if stmt.Value, err = p.parseRawUntilClose(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// ParseAttach parses SQLite's ATTACH [DATABASE] file AS schema; dialects
+// register it as a StatementParser
+func (p *Parser) ParseAttach() (ast.Statement, error) {
+	pos := p.peek().Start
+	stmt := &ast.AttachStmt{Pos: pos, Attach: p.acceptDatabase()}
+	var err error
+	if stmt.File, err = p.ParseExpr(); err != nil {
+		return nil, err
+	}
+	if stmt.As, err = p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	if stmt.Schema, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// ParseDetach parses SQLite's DETACH [DATABASE] schema; dialects register
+// it as a StatementParser
+func (p *Parser) ParseDetach() (ast.Statement, error) {
+	pos := p.peek().Start
+	stmt := &ast.DetachStmt{Pos: pos, Detach: p.acceptDatabase()}
+	var err error
+	if stmt.Schema, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// acceptDatabase 消耗ATTACH或DETACH及可选的DATABASE，返回其原文
+func (p *Parser) acceptDatabase() ast.Keyword {
+	kw := ast.Keyword(p.next().Value)
+	if database, ok := p.acceptKeyword("DATABASE"); ok {
+		kw += " " + database
+	}
+	return kw
+}
+
+// dropObjects DROP语句支持的对象类型
+var dropObjects = [][]string{
+	{"TEMPORARY", "TABLE"}, {"TABLE"}, {"MATERIALIZED", "VIEW"}, {"VIEW"},
+	{"INDEX", "CONCURRENTLY"}, {"INDEX"}, {"SEQUENCE"}, {"SCHEMA"},
+}
+
+// parseDrop 解析DROP语句
+func (p *Parser) parseDrop() (*ast.DropStmt, error) {
+	pos := p.peek().Start
+	if _, err := p.expectKeyword("DROP"); err != nil {
+		return nil, err
+	}
+	stmt := &ast.DropStmt{Pos: pos}
+	for _, words := range dropObjects {
+		if kw, ok := p.acceptKeyword(words...); ok {
+			stmt.Object = kw
+			break
+		}
+	}
+	if stmt.Object == "" {
+		return nil, p.errorf("unsupported statement %q", "DROP "+p.peek().Value)
+	}
+	stmt.IfExists, _ = p.acceptKeyword("IF", "EXISTS")
+	for {
+		name, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		stmt.Names = append(stmt.Names, name)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	if on, ok := p.acceptKeyword("ON"); ok {
+		var err error
+		if stmt.Table, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+		stmt.On = on
+	}
+	if p.isKeyword("CASCADE", "RESTRICT") {
+		stmt.Behavior = ast.Keyword(p.next().Value)
+	}
+	return stmt, nil
+}
+
+// rawExpr 将tokens[start:end]原样拼接为RawExpr
+func (p *Parser) rawExpr(start, end int) *ast.RawExpr {
+	return &ast.RawExpr{Pos: p.tokens[start].Start, Text: joinTokens(p.tokens[start:end])}
+}
@@ -0,0 +1,455 @@
+package parser
+
+import (
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+// parseInsert 解析INSERT语句
+func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
+	pos := p.peek().Start
+	insert, err := p.parseInsertVerb()
+	if err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("INTO")
+	table, err := p.parseName(false)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.InsertStmt{Pos: pos, Insert: insert, Table: table}
+
+	// 括号中是查询时没有列名列表
+	if p.peek().IsPunct("(") && !p.isSubqueryStart() {
+		if stmt.Columns, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isKeyword("OUTPUT") {
+		if stmt.Output, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case p.isKeywordSeq("DEFAULT", "VALUES"):
+		stmt.DefaultValues, _ = p.acceptKeyword("DEFAULT", "VALUES")
+	case p.isKeyword("VALUES"):
+		p.next()
+		if stmt.Values, err = p.parseValuesRows(); err != nil {
+			return nil, err
+		}
+	default:
+		if stmt.Query, err = p.parseQuery(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isKeywordSeq("ON", "CONFLICT") || p.isKeywordSeq("ON", "DUPLICATE") {
+		if stmt.OnConflict, err = p.parseOnConflict(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("RETURNING") {
+		if stmt.Returning, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseInsertVerb 解析 INSERT [IGNORE]、INSERT OR REPLACE/IGNORE/ABORT/FAIL/ROLLBACK 或 REPLACE
+func (p *Parser) parseInsertVerb() (ast.Keyword, error) {
+	if kw, ok := p.acceptKeyword("REPLACE"); ok {
+		return kw, nil
+	}
+	insert, err := p.expectKeyword("INSERT")
+	if err != nil {
+		return "", err
+	}
+	if kw, ok := p.acceptKeyword("IGNORE"); ok {
+		return insert + " " + kw, nil
+	}
+	if kw, ok := p.acceptConflict(); ok {
+		return insert + " " + kw, nil
+	}
+	return insert, nil
+}
+
+// conflictResolutions INSERT OR 与 UPDATE OR 之后的冲突处理方式
+var conflictResolutions = []string{"ROLLBACK", "ABORT", "REPLACE", "FAIL", "IGNORE"}
+
+// acceptConflict 若当前为 OR 加冲突处理方式则消耗并返回其原文
+func (p *Parser) acceptConflict() (ast.Keyword, bool) {
+	for _, resolution := range conflictResolutions {
+		if kw, ok := p.acceptKeyword("OR", resolution); ok {
+			return kw, true
+		}
+	}
+	return "", false
+}
+
+// parseOnConflict 解析 ON CONFLICT ... 或 ON DUPLICATE KEY UPDATE ...
+func (p *Parser) parseOnConflict() (*ast.OnConflict, error) {
+	clause := &ast.OnConflict{Pos: p.peek().Start}
+	var err error
+	if kw, ok := p.acceptKeyword("ON", "DUPLICATE", "KEY", "UPDATE"); ok {
+		clause.Clause = kw
+		if clause.Set, err = p.parseAssignments(); err != nil {
+			return nil, err
+		}
+		return clause, nil
+	}
+
+	if clause.Clause, err = p.expectKeyword("ON", "CONFLICT"); err != nil {
+		return nil, err
+	}
+	if p.acceptPunct("(") {
+		if clause.Target, err = p.parseRawUntilClose(); err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptKeyword("WHERE"); ok {
+			if clause.TargetWhere, err = p.ParseExpr(); err != nil {
+				return nil, err
+			}
+		}
+	} else if kw, ok := p.acceptKeyword("ON", "CONSTRAINT"); ok {
+		clause.OnConstraint = kw
+		if clause.Constraint, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+	}
+	if kw, ok := p.acceptKeyword("DO", "NOTHING"); ok {
+		clause.Action = kw
+		return clause, nil
+	}
+	if clause.Action, err = p.expectKeyword("DO", "UPDATE", "SET"); err != nil {
+		return nil, err
+	}
+	if clause.Set, err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+	if _, ok := p.acceptKeyword("WHERE"); ok {
+		if clause.Where, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return clause, nil
+}
+
+// parseValuesRows 解析VALUES之后逗号分隔的行
+func (p *Parser) parseValuesRows() ([][]ast.Expr, error) {
+	var rows [][]ast.Expr
+	for {
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		row, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		if !p.acceptPunct(",") {
+			return rows, nil
+		}
+	}
+}
+
+// parseUpdate 解析UPDATE语句
+func (p *Parser) parseUpdate() (*ast.UpdateStmt, error) {
+	pos := p.peek().Start
+	if _, err := p.expectKeyword("UPDATE"); err != nil {
+		return nil, err
+	}
+	conflict, _ := p.acceptConflict()
+	table, err := p.parseJoinedTable()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.UpdateStmt{Pos: pos, Conflict: conflict, Table: table}
+
+	if _, err := p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+	if stmt.Set, err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+	if p.isKeyword("OUTPUT") {
+		if stmt.Output, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("FROM"); ok {
+		if stmt.From, err = p.parseTableExprs(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.Where, stmt.OrderBy, stmt.Limit, err = p.parseDMLTail(); err != nil {
+		return nil, err
+	}
+	if p.isKeyword("RETURNING") {
+		if stmt.Returning, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseDMLTail 解析UPDATE和DELETE末尾可选的WHERE、ORDER BY和LIMIT
+func (p *Parser) parseDMLTail() (ast.Expr, []*ast.OrderItem, *ast.Limit, error) {
+	var where ast.Expr
+	var orderBy []*ast.OrderItem
+	var err error
+	if _, ok := p.acceptKeyword("WHERE"); ok {
+		if where, err = p.ParseExpr(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("ORDER", "BY"); ok {
+		if orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	limit, err := p.parseLimit()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return where, orderBy, limit, nil
+}
+
+// parseAssignments 解析SET子句中逗号分隔的赋值
+func (p *Parser) parseAssignments() ([]*ast.Assignment, error) {
+	var assignments []*ast.Assignment
+	for {
+		column, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOperator("=") {
+			return nil, p.errorf("expected %q", "=")
+		}
+		p.next()
+		value, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, &ast.Assignment{Column: column, Value: value})
+		if !p.acceptPunct(",") {
+			return assignments, nil
+		}
+	}
+}
+
+// parseDelete 解析DELETE语句
+func (p *Parser) parseDelete() (*ast.DeleteStmt, error) {
+	pos := p.peek().Start
+	if _, err := p.expectKeyword("DELETE"); err != nil {
+		return nil, err
+	}
+	stmt := &ast.DeleteStmt{Pos: pos}
+
+	// MySQL多表删除：DELETE t1, t2 FROM ...
+	if !p.isKeyword("FROM") {
+		for {
+			target, err := p.parseName(true)
+			if err != nil {
+				return nil, err
+			}
+			stmt.Targets = append(stmt.Targets, target)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+	if _, err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if len(stmt.Targets) > 0 {
+		if stmt.From, err = p.parseTableExprs(); err != nil {
+			return nil, err
+		}
+	} else {
+		if stmt.Table, err = p.parseTablePrimary(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("OUTPUT") {
+			if stmt.Output, err = p.parseReturning(); err != nil {
+				return nil, err
+			}
+		}
+		// SQL Server：DELETE FROM t FROM t JOIN ...
+		if _, ok := p.acceptKeyword("FROM"); ok {
+			if stmt.From, err = p.parseTableExprs(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, ok := p.acceptKeyword("USING"); ok {
+		if stmt.Using, err = p.parseTableExprs(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.Where, stmt.OrderBy, stmt.Limit, err = p.parseDMLTail(); err != nil {
+		return nil, err
+	}
+	if p.isKeyword("RETURNING") {
+		if stmt.Returning, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseMerge 解析MERGE语句
+func (p *Parser) parseMerge() (*ast.MergeStmt, error) {
+	pos := p.peek().Start
+	if _, err := p.expectKeyword("MERGE"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("INTO")
+	stmt := &ast.MergeStmt{Pos: pos}
+	var err error
+	if stmt.Target, err = p.parseTablePrimary(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("USING"); err != nil {
+		return nil, err
+	}
+	if stmt.Source, err = p.parseTablePrimary(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+	if stmt.On, err = p.ParseExpr(); err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("WHEN") {
+		when, err := p.parseMergeWhen()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Whens = append(stmt.Whens, when)
+	}
+	if len(stmt.Whens) == 0 {
+		return nil, p.errorf("expected WHEN")
+	}
+
+	if p.isKeyword("OUTPUT") {
+		if stmt.Output, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("RETURNING") {
+		if stmt.Returning, err = p.parseReturning(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// mergeMatches WHEN之后的匹配条件
+var mergeMatches = [][]string{
+	{"MATCHED"}, {"NOT", "MATCHED", "BY", "SOURCE"}, {"NOT", "MATCHED", "BY", "TARGET"}, {"NOT", "MATCHED"},
+}
+
+// parseMergeWhen 解析MERGE的WHEN分支
+func (p *Parser) parseMergeWhen() (*ast.MergeWhen, error) {
+	when := &ast.MergeWhen{Pos: p.peek().Start}
+	if _, err := p.expectKeyword("WHEN"); err != nil {
+		return nil, err
+	}
+	for _, words := range mergeMatches {
+		if kw, ok := p.acceptKeyword(words...); ok {
+			when.Match = kw
+			break
+		}
+	}
+	if when.Match == "" {
+		return nil, p.errorf("expected MATCHED or NOT MATCHED")
+	}
+	var err error
+	if _, ok := p.acceptKeyword("AND"); ok {
+		if when.Cond, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.expectKeyword("THEN"); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.isKeywordSeq("UPDATE", "SET"):
+		when.Action, _ = p.acceptKeyword("UPDATE", "SET")
+		if when.Set, err = p.parseAssignments(); err != nil {
+			return nil, err
+		}
+	case p.isKeyword("DELETE"):
+		when.Action, _ = p.acceptKeyword("DELETE")
+	case p.isKeywordSeq("DO", "NOTHING"):
+		when.Action, _ = p.acceptKeyword("DO", "NOTHING")
+	case p.isKeyword("INSERT"):
+		when.Action, _ = p.acceptKeyword("INSERT")
+		if p.peek().IsPunct("(") {
+			if when.Columns, err = p.parseColumnList(); err != nil {
+				return nil, err
+			}
+		}
+		if kw, ok := p.acceptKeyword("DEFAULT", "VALUES"); ok {
+			when.DefaultValues = kw
+			break
+		}
+		if _, err := p.expectKeyword("VALUES"); err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		if when.Values, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("expected UPDATE, DELETE, INSERT or DO NOTHING")
+	}
+	return when, nil
+}
+
+// parseReturning 解析 RETURNING 列表或 OUTPUT 列表 [INTO 目标 [(列)]]
+func (p *Parser) parseReturning() (*ast.ReturningClause, error) {
+	clause := &ast.ReturningClause{Pos: p.peek().Start, Keyword: ast.Keyword(p.next().Value)}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		clause.Columns = append(clause.Columns, item)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	if into, ok := p.acceptKeyword("INTO"); ok {
+		var err error
+		if tok := p.peek(); tok.Type == lexer.Placeholder {
+			// 表变量，如 SQL Server 的 OUTPUT ... INTO @ids
+			clause.Target = &ast.Name{Pos: tok.Start, Parts: []string{p.next().Value}}
+		} else if clause.Target, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+		if p.peek().IsPunct("(") {
+			if clause.TargetNames, err = p.parseColumnList(); err != nil {
+				return nil, err
+			}
+		}
+		clause.Into = into
+	}
+	return clause, nil
+}
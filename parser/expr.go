@@ -0,0 +1,606 @@
+package parser
+
+import (
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+// bitwiseOperators 拼接与位运算符
+var bitwiseOperators = []string{"||", "|", "&", "^", "<<", ">>"}
+
+// typedLiteralPrefixes 可以直接跟字符串的类型关键字，如 DATE '2024-01-01'
+var typedLiteralPrefixes = []string{"DATE", "TIME", "TIMESTAMP", "INTERVAL"}
+
+// intervalUnits INTERVAL字面量之后可出现的时间单位
+var intervalUnits = []string{
+	"YEAR", "MONTH", "WEEK", "DAY", "HOUR", "MINUTE", "SECOND",
+	"YEARS", "MONTHS", "WEEKS", "DAYS", "HOURS", "MINUTES", "SECONDS",
+}
+
+// ParseExpr parses a single expression
+func (p *Parser) ParseExpr() (ast.Expr, error) {
+	return p.parseOr()
+}
+
+// parseOr 解析OR表达式
+func (p *Parser) parseOr() (ast.Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		op := p.next().Value
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.BinaryExpr{Left: left, Op: op, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd 解析AND表达式
+func (p *Parser) parseAnd() (ast.Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		op := p.next().Value
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.BinaryExpr{Left: left, Op: op, Right: right}
+	}
+	return left, nil
+}
+
+// parseNot 解析NOT前缀
+func (p *Parser) parseNot() (ast.Expr, error) {
+	if p.isKeyword("NOT") && !p.peekAt(1).IsKeyword("EXISTS") {
+		tok := p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.UnaryExpr{Pos: tok.Start, Op: tok.Value, Expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison 解析比较、LIKE、IN、BETWEEN、IS等谓词
+func (p *Parser) parseComparison() (ast.Expr, error) {
+	left, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.Type == lexer.Operator && !p.isOperator(bitwiseOperators...) &&
+			!p.isOperator("+", "-", "*", "/", "%"):
+			op := p.next().Value
+			right, err := p.parseBitwise()
+			if err != nil {
+				return nil, err
+			}
+			left = &ast.BinaryExpr{Left: left, Op: op, Right: right}
+		case p.isKeyword("IS"):
+			left, err = p.parseIs(left)
+		case p.isKeyword("LIKE", "ILIKE") || p.isKeywordSeq("NOT", "LIKE") || p.isKeywordSeq("NOT", "ILIKE"):
+			left, err = p.parseLike(left)
+		case p.isKeyword("IN") || p.isKeywordSeq("NOT", "IN"):
+			left, err = p.parseIn(left)
+		case p.isKeyword("BETWEEN") || p.isKeywordSeq("NOT", "BETWEEN"):
+			left, err = p.parseBetween(left)
+		default:
+			return left, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseIs 解析 IS [NOT] NULL/TRUE/FALSE/DISTINCT FROM
+func (p *Parser) parseIs(left ast.Expr) (ast.Expr, error) {
+	op := p.next().Value
+	if kw, ok := p.acceptKeyword("NOT"); ok {
+		op += " " + string(kw)
+	}
+	if kw, ok := p.acceptKeyword("DISTINCT", "FROM"); ok {
+		op += " " + string(kw)
+	}
+	right, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.BinaryExpr{Left: left, Op: op, Right: right}, nil
+}
+
+// parseLike 解析 [NOT] LIKE|ILIKE pattern [ESCAPE char]
+func (p *Parser) parseLike(left ast.Expr) (ast.Expr, error) {
+	op := p.next().Value
+	if p.isKeyword("LIKE", "ILIKE") {
+		op += " " + p.next().Value
+	}
+	right, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	var expr ast.Expr = &ast.BinaryExpr{Left: left, Op: op, Right: right}
+	if p.isKeyword("ESCAPE") {
+		escapeOp := p.next().Value
+		escape, err := p.parseBitwise()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.BinaryExpr{Left: expr, Op: escapeOp, Right: escape}
+	}
+	return expr, nil
+}
+
+// parseIn 解析 [NOT] IN (list | subquery)
+func (p *Parser) parseIn(left ast.Expr) (ast.Expr, error) {
+	op, ok := p.acceptKeyword("IN")
+	if !ok {
+		op, _ = p.acceptKeyword("NOT", "IN")
+	}
+	in := &ast.InExpr{Expr: left, Op: op}
+	if p.isSubqueryStart() {
+		subquery, err := p.parseSubquery()
+		if err != nil {
+			return nil, err
+		}
+		in.Subquery = subquery
+		return in, nil
+	}
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	list, err := p.parseExprList()
+	if err != nil {
+		return nil, err
+	}
+	in.List = list
+	return in, p.expectPunct(")")
+}
+
+// parseBetween 解析 [NOT] BETWEEN low AND high
+func (p *Parser) parseBetween(left ast.Expr) (ast.Expr, error) {
+	op, ok := p.acceptKeyword("BETWEEN")
+	if !ok {
+		op, _ = p.acceptKeyword("NOT", "BETWEEN")
+	}
+	low, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	and, err := p.expectKeyword("AND")
+	if err != nil {
+		return nil, err
+	}
+	high, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.BetweenExpr{Expr: left, Op: op, Low: low, And: and, High: high}, nil
+}
+
+// parseBitwise 解析拼接与位运算
+func (p *Parser) parseBitwise() (ast.Expr, error) {
+	return p.parseBinaryLevel(bitwiseOperators, p.parseAdditive)
+}
+
+// parseAdditive 解析加减
+func (p *Parser) parseAdditive() (ast.Expr, error) {
+	return p.parseBinaryLevel([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+// parseMultiplicative 解析乘除取模
+func (p *Parser) parseMultiplicative() (ast.Expr, error) {
+	return p.parseBinaryLevel([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+// parseBinaryLevel 解析左结合的二元运算
+func (p *Parser) parseBinaryLevel(ops []string, operand func() (ast.Expr, error)) (ast.Expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(ops...) {
+		op := p.next().Value
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.BinaryExpr{Left: left, Op: op, Right: right}
+	}
+	return left, nil
+}
+
+// parseUnary 解析一元正负号、按位取反与层次查询中的PRIOR
+func (p *Parser) parseUnary() (ast.Expr, error) {
+	if p.isOperator("-", "+", "~") || p.isKeyword("PRIOR") {
+		tok := p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.UnaryExpr{Pos: tok.Start, Op: tok.Value, Expr: expr}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix 解析基本表达式之后的 ::type 类型转换、数组下标与 (+) 外连接标记
+func (p *Parser) parsePostfix() (ast.Expr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOperator("::"):
+			as := p.next().Value
+			dataType, err := p.parseDataType()
+			if err != nil {
+				return nil, err
+			}
+			expr = &ast.CastExpr{Pos: ast.Pos(expr), Expr: expr, As: ast.Keyword(as), Type: dataType}
+		case p.peek().IsPunct("["):
+			index, err := p.parseSubscript()
+			if err != nil {
+				return nil, err
+			}
+			expr = &ast.SubscriptExpr{Expr: expr, Index: index}
+		case p.isOuterJoinMarker():
+			p.pos += 3
+			expr = &ast.OuterJoinExpr{Expr: expr}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+// parsePrimary 解析基本表达式
+func (p *Parser) parsePrimary() (ast.Expr, error) {
+	tok := p.peek()
+	switch {
+	case tok.Type == lexer.Number || tok.Type == lexer.String:
+		return &ast.Literal{Pos: tok.Start, Value: p.next().Value}, nil
+	case tok.Type == lexer.Placeholder:
+		return &ast.Placeholder{Pos: tok.Start, Value: p.next().Value}, nil
+	case tok.IsKeyword("NULL", "TRUE", "FALSE", "DEFAULT"):
+		return &ast.Literal{Pos: tok.Start, Value: p.next().Value}, nil
+	case tok.IsPunct("("):
+		return p.parseParen()
+	case tok.IsKeyword("EXISTS") || (tok.IsKeyword("NOT") && p.peekAt(1).IsKeyword("EXISTS")):
+		return p.parseExists()
+	case tok.IsKeyword("CASE"):
+		return p.parseCase()
+	case tok.IsKeyword("CAST") && p.peekAt(1).IsPunct("("):
+		return p.parseCast()
+	case tok.IsKeyword("ARRAY") && p.peekAt(1).IsPunct("["):
+		return p.parseArray()
+	case tok.Type == lexer.Operator && tok.Value == "*":
+		return &ast.Name{Pos: tok.Start, Parts: []string{p.next().Value}}, nil
+	case isTypedLiteral(tok) && p.peekAt(1).Type == lexer.String:
+		return p.parseTypedLiteral()
+	case tok.Type == lexer.Keyword && p.peekAt(1).IsPunct("("):
+		// 关键字作为函数名，如 LEFT(name, 3)、REPLACE(a, b, c)
+		name := &ast.Name{Pos: tok.Start, Parts: []string{p.next().Value}}
+		return p.parseFuncCall(name)
+	case p.isNameToken(tok):
+		name, err := p.parseName(true)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().IsPunct("(") && !p.isOuterJoinMarker() {
+			return p.parseFuncCall(name)
+		}
+		return name, nil
+	}
+	return nil, p.unexpected()
+}
+
+// isOuterJoinMarker 当前位置是否为 (+) 外连接标记
+func (p *Parser) isOuterJoinMarker() bool {
+	return p.peek().IsPunct("(") && p.peekAt(1).Type == lexer.Operator && p.peekAt(1).Value == "+" &&
+		p.peekAt(2).IsPunct(")")
+}
+
+// isTypedLiteral 词法单元是否为带类型字面量的前缀
+func isTypedLiteral(tok lexer.Token) bool {
+	if tok.Type != lexer.Keyword && tok.Type != lexer.Identifier {
+		return false
+	}
+	upper := tok.Upper()
+	for _, prefix := range typedLiteralPrefixes {
+		if upper == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTypedLiteral 解析 DATE '...'、INTERVAL '1' DAY 等
+func (p *Parser) parseTypedLiteral() (ast.Expr, error) {
+	start := p.pos
+	prefix := p.next()
+	p.next()
+	if prefix.Upper() == "INTERVAL" {
+		for _, unit := range intervalUnits {
+			if p.peek().Type != lexer.String && p.peek().Upper() == unit {
+				p.next()
+				break
+			}
+		}
+	}
+	return &ast.Literal{Pos: prefix.Start, Value: joinTokens(p.tokens[start:p.pos])}, nil
+}
+
+// parseParen 解析括号表达式、元组或子查询
+func (p *Parser) parseParen() (ast.Expr, error) {
+	if p.isSubqueryStart() {
+		return p.parseSubquery()
+	}
+	pos := p.next().Start
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().IsPunct(",") {
+		exprs := []ast.Expr{expr}
+		for p.acceptPunct(",") {
+			expr, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+		return &ast.TupleExpr{Pos: pos, Exprs: exprs}, p.expectPunct(")")
+	}
+	rparen := p.peek().Start
+	return &ast.ParenExpr{Pos: pos, Expr: expr, Rparen: rparen}, p.expectPunct(")")
+}
+
+// isSubqueryStart 当前位置是否为括号中的子查询
+func (p *Parser) isSubqueryStart() bool {
+	return p.peek().IsPunct("(") && p.peekAt(1).IsKeyword("SELECT", "WITH")
+}
+
+// parseSubquery 解析括号中的子查询
+func (p *Parser) parseSubquery() (*ast.SubqueryExpr, error) {
+	pos := p.peek().Start
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	stmt, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	rparen := p.peek().Start
+	return &ast.SubqueryExpr{Pos: pos, Select: stmt, Rparen: rparen}, p.expectPunct(")")
+}
+
+// parseExists 解析 [NOT] EXISTS (subquery)
+func (p *Parser) parseExists() (ast.Expr, error) {
+	var not *lexer.Token
+	if p.isKeyword("NOT") {
+		tok := p.next()
+		not = &tok
+	}
+	exists := p.next()
+	subquery, err := p.parseSubquery()
+	if err != nil {
+		return nil, err
+	}
+	var expr ast.Expr = &ast.ExistsExpr{Pos: exists.Start, Exists: ast.Keyword(exists.Value), Subquery: subquery}
+	if not != nil {
+		expr = &ast.UnaryExpr{Pos: not.Start, Op: not.Value, Expr: expr}
+	}
+	return expr, nil
+}
+
+// parseCase 解析CASE表达式
+func (p *Parser) parseCase() (ast.Expr, error) {
+	expr := &ast.CaseExpr{Pos: p.next().Start}
+	if !p.isKeyword("WHEN") {
+		operand, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Operand = operand
+	}
+	for p.isKeyword("WHEN") {
+		p.next()
+		cond, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		result, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Whens = append(expr.Whens, &ast.WhenClause{Cond: cond, Result: result})
+	}
+	if len(expr.Whens) == 0 {
+		return nil, p.errorf("expected WHEN")
+	}
+	if _, ok := p.acceptKeyword("ELSE"); ok {
+		elseExpr, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Else = elseExpr
+	}
+	expr.End = p.peek().Start
+	if _, err := p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// parseCast 解析 CAST(expr AS type)
+func (p *Parser) parseCast() (ast.Expr, error) {
+	tok := p.next()
+	p.next()
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	as, err := p.expectKeyword("AS")
+	if err != nil {
+		return nil, err
+	}
+	dataType, err := p.parseDataType()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.CastExpr{Pos: tok.Start, Cast: ast.Keyword(tok.Value), Expr: expr, As: as, Type: dataType}, p.expectPunct(")")
+}
+
+// parseSubscript 原样收集方括号中的下标或切片，并消耗两侧的方括号
+func (p *Parser) parseSubscript() (string, error) {
+	p.next()
+	start := p.pos
+	depth := 0
+	for !p.atEnd() {
+		switch tok := p.peek(); {
+		case tok.IsPunct("["):
+			depth++
+		case tok.IsPunct("]"):
+			if depth == 0 {
+				index := joinTokens(p.tokens[start:p.pos])
+				p.next()
+				return index, nil
+			}
+			depth--
+		}
+		p.next()
+	}
+	return "", p.errorf("expected %q", "]")
+}
+
+// parseArray 解析 ARRAY[...] 数组构造
+func (p *Parser) parseArray() (ast.Expr, error) {
+	tok := p.next()
+	p.next()
+	array := &ast.ArrayExpr{Pos: tok.Start, Array: ast.Keyword(tok.Value)}
+	if p.acceptPunct("]") {
+		return array, nil
+	}
+	elems, err := p.parseExprList()
+	if err != nil {
+		return nil, err
+	}
+	array.Elems = elems
+	return array, p.expectPunct("]")
+}
+
+// parseFuncCall 解析函数调用的参数部分，无法识别的参数原样保留
+func (p *Parser) parseFuncCall(name *ast.Name) (ast.Expr, error) {
+	p.next()
+	call := &ast.FuncCall{Name: name}
+	if p.acceptPunct(")") {
+		return p.parseOver(call)
+	}
+
+	start := p.pos
+	if kw, ok := p.acceptKeyword("DISTINCT"); ok {
+		call.Distinct = kw
+	} else if kw, ok := p.acceptKeyword("ALL"); ok {
+		call.Distinct = kw
+	}
+	args, err := p.parseExprList()
+	if err == nil && p.acceptPunct(")") {
+		call.Args = args
+		return p.parseOver(call)
+	}
+
+	// 回退：保留括号内的原文
+	p.pos = start
+	call.Distinct = ""
+	raw, err := p.parseRawUntilClose()
+	if err != nil {
+		return nil, err
+	}
+	call.Args = []ast.Expr{raw}
+	return p.parseOver(call)
+}
+
+// parseRawUntilClose 原样收集到匹配的右括号为止，并消耗右括号
+func (p *Parser) parseRawUntilClose() (*ast.RawExpr, error) {
+	start := p.pos
+	depth := 0
+	for !p.atEnd() {
+		tok := p.peek()
+		switch {
+		case tok.IsPunct("("):
+			depth++
+		case tok.IsPunct(")"):
+			if depth == 0 {
+				raw := &ast.RawExpr{Pos: p.tokens[start].Start, Text: joinTokens(p.tokens[start:p.pos])}
+				p.next()
+				return raw, nil
+			}
+			depth--
+		}
+		p.next()
+	}
+	return nil, p.errorf("expected %q", ")")
+}
+
+// parseExprList 解析逗号分隔的表达式列表
+func (p *Parser) parseExprList() ([]ast.Expr, error) {
+	var exprs []ast.Expr
+	for {
+		expr, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.acceptPunct(",") {
+			return exprs, nil
+		}
+	}
+}
+
+// typeContinuations 多词类型名中可以跟在首个词之后的词
+var typeContinuations = map[string]bool{
+	"PRECISION": true, "VARYING": true, "UNSIGNED": true, "SIGNED": true, "ZEROFILL": true,
+}
+
+// parseDataType 解析类型名，如 VARCHAR(255)、DOUBLE PRECISION、TIMESTAMP WITH TIME ZONE
+func (p *Parser) parseDataType() (*ast.DataType, error) {
+	start := p.pos
+	if !p.isNameToken(p.peek()) {
+		return nil, p.errorf("expected type name")
+	}
+	p.next()
+	for typeContinuations[p.peek().Upper()] {
+		p.next()
+	}
+	if p.peek().IsPunct("(") {
+		p.next()
+		if _, err := p.parseRawUntilClose(); err != nil {
+			return nil, err
+		}
+	}
+	for typeContinuations[p.peek().Upper()] {
+		p.next()
+	}
+	if p.isKeywordSeq("WITH") || p.peek().Upper() == "WITHOUT" {
+		if p.peekAt(1).Upper() == "TIME" && p.peekAt(2).Upper() == "ZONE" {
+			p.pos += 3
+		}
+	}
+	for p.peek().IsPunct("[") && p.peekAt(1).IsPunct("]") {
+		p.pos += 2
+	}
+	return &ast.DataType{Text: joinTokens(p.tokens[start:p.pos])}, nil
+}
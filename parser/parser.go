@@ -0,0 +1,373 @@
+// Package parser builds an ast from SQL text.
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+// Error is a syntax error at a byte offset of the input
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// reserved 不能作为隐式别名或裸标识符使用的关键字
+var reserved = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		ALL AND ANY AS ASC BETWEEN BY CASE CAST CHECK CONSTRAINT CREATE CROSS
+		DEFAULT DELETE DESC DISTINCT DROP ELSE END EXCEPT EXISTS FALSE FETCH
+		FOREIGN FROM FULL GROUP HAVING IN INNER INSERT INTERSECT INTO IS JOIN
+		LATERAL LEFT LIKE LIMIT NATURAL NOT NULL OFFSET ON OR ORDER OUTER OVER PRIMARY
+		REFERENCES RETURNING RIGHT SELECT SET STRAIGHT_JOIN THEN TRUE UNION UNIQUE UPDATE USING VALUES
+		WHEN WHERE WINDOW WITH
+	`) {
+		reserved[word] = true
+	}
+}
+
+// aliasStops 可以作为标识符但不能作为隐式别名的关键字，用于识别紧跟在表名之后的子句
+var aliasStops = map[string]bool{
+	"OUTPUT": true,
+}
+
+// StatementParser parses a dialect statement starting at the current token
+type StatementParser func(p *Parser) (ast.Statement, error)
+
+// Config holds the syntax rules of a SQL dialect
+type Config struct {
+	// Reserved are keywords that cannot be bare identifiers or implicit
+	// aliases in addition to the common ones
+	Reserved []string
+	// Statements parse statements the common grammar does not know, keyed
+	// by their upper-case first word; they take precedence over the
+	// common statements
+	Statements map[string]StatementParser
+}
+
+// Parser parses a token stream into statements
+type Parser struct {
+	tokens     []lexer.Token // 仅包含有效词法单元（不含空白和注释）
+	comments   []*ast.Comment
+	pos        int
+	end        int             // 输入结束处的字节偏移
+	reserved   map[string]bool // 方言额外的保留字
+	statements map[string]StatementParser
+}
+
+// New creates a parser over tokens produced by the lexer using the generic grammar
+func New(tokens []lexer.Token) *Parser {
+	return NewWithConfig(tokens, &Config{})
+}
+
+// NewWithConfig creates a parser over tokens produced by the lexer using the grammar of a dialect
+func NewWithConfig(tokens []lexer.Token, cfg *Config) *Parser {
+	p := &Parser{reserved: map[string]bool{}, statements: cfg.Statements}
+	for _, word := range cfg.Reserved {
+		p.reserved[strings.ToUpper(word)] = true
+	}
+	lineHasCode := false
+	for _, tok := range tokens {
+		switch tok.Type {
+		case lexer.Whitespace:
+			if strings.Contains(tok.Value, "\n") {
+				lineHasCode = false
+			}
+		case lexer.Comment:
+			p.comments = append(p.comments, &ast.Comment{Text: tok.Value, Pos: tok.Start, Trailing: lineHasCode})
+			lineHasCode = true
+		default:
+			p.tokens = append(p.tokens, tok)
+			lineHasCode = true
+		}
+	}
+	if len(tokens) > 0 {
+		p.end = tokens[len(tokens)-1].End
+	}
+	return p
+}
+
+// Parse parses SQL text into a list of statements
+func Parse(sql string) ([]ast.Statement, error) {
+	tokens, err := lexer.Tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTokens(tokens)
+}
+
+// ParseTokens parses tokens produced by the lexer into a list of statements
+func ParseTokens(tokens []lexer.Token) ([]ast.Statement, error) {
+	return New(tokens).ParseAll()
+}
+
+// Comments returns all comments of the input in source order
+func (p *Parser) Comments() []*ast.Comment {
+	return p.comments
+}
+
+// ParseAll parses all remaining statements separated by semicolons
+func (p *Parser) ParseAll() ([]ast.Statement, error) {
+	var stmts []ast.Statement
+	for {
+		for p.acceptPunct(";") {
+		}
+		if p.atEnd() {
+			return stmts, nil
+		}
+		stmt, err := p.ParseStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		if !p.atEnd() && !p.peek().IsPunct(";") {
+			return nil, p.unexpected()
+		}
+	}
+}
+
+// ParseStatement parses a single statement
+func (p *Parser) ParseStatement() (ast.Statement, error) {
+	if tok := p.peek(); tok.Type == lexer.Keyword || tok.Type == lexer.Identifier {
+		if parse, ok := p.statements[tok.Upper()]; ok {
+			return parse(p)
+		}
+	}
+
+	var stmt ast.Statement
+	var err error
+	switch {
+	case p.isKeyword("WITH"):
+		stmt, err = p.parseWith()
+	case p.isKeyword("SELECT") || p.peek().IsPunct("("):
+		stmt, err = p.parseSetOperation()
+	case p.isKeyword("INSERT", "REPLACE"):
+		stmt, err = p.parseInsert()
+	case p.isKeyword("UPDATE"):
+		stmt, err = p.parseUpdate()
+	case p.isKeyword("DELETE"):
+		stmt, err = p.parseDelete()
+	case p.isKeyword("MERGE"):
+		stmt, err = p.parseMerge()
+	case p.isKeyword("CREATE"):
+		stmt, err = p.parseCreate()
+	case p.isKeyword("ALTER"):
+		stmt, err = p.parseAlter()
+	case p.isKeyword("DROP"):
+		stmt, err = p.parseDrop()
+	case p.atEnd():
+		return nil, p.errorf("expected statement")
+	default:
+		return nil, p.errorf("unsupported statement %q", p.peek().Value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// atEnd 是否已到达输入末尾
+func (p *Parser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek 查看当前词法单元，到达末尾时返回空白词法单元作为哨兵
+func (p *Parser) peek() lexer.Token {
+	return p.peekAt(0)
+}
+
+// peekAt 查看当前位置之后第n个词法单元
+func (p *Parser) peekAt(n int) lexer.Token {
+	if p.pos+n >= len(p.tokens) {
+		return lexer.Token{Type: lexer.Whitespace, Start: p.end, End: p.end}
+	}
+	return p.tokens[p.pos+n]
+}
+
+// next 返回当前词法单元并前进
+func (p *Parser) next() lexer.Token {
+	tok := p.peek()
+	if !p.atEnd() {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword 当前词法单元是否为给定关键字之一
+func (p *Parser) isKeyword(words ...string) bool {
+	return p.peek().IsKeyword(words...)
+}
+
+// isKeywordSeq 当前位置是否以给定关键字序列开头
+func (p *Parser) isKeywordSeq(words ...string) bool {
+	for i, word := range words {
+		if !p.peekAt(i).IsKeyword(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword 若当前位置以给定关键字序列开头则消耗并返回其原文
+func (p *Parser) acceptKeyword(words ...string) (ast.Keyword, bool) {
+	if !p.isKeywordSeq(words...) {
+		return "", false
+	}
+	values := make([]string, len(words))
+	for i := range words {
+		values[i] = p.next().Value
+	}
+	return ast.Keyword(strings.Join(values, " ")), true
+}
+
+// expectKeyword 消耗给定关键字序列，不匹配时返回错误
+func (p *Parser) expectKeyword(words ...string) (ast.Keyword, error) {
+	kw, ok := p.acceptKeyword(words...)
+	if !ok {
+		return "", p.errorf("expected %s", strings.Join(words, " "))
+	}
+	return kw, nil
+}
+
+// acceptPunct 若当前为给定标点则消耗
+func (p *Parser) acceptPunct(punct string) bool {
+	if p.peek().IsPunct(punct) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expectPunct 消耗给定标点，不匹配时返回错误
+func (p *Parser) expectPunct(punct string) error {
+	if !p.acceptPunct(punct) {
+		return p.errorf("expected %q", punct)
+	}
+	return nil
+}
+
+// isOperator 当前词法单元是否为给定运算符之一
+func (p *Parser) isOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.Type != lexer.Operator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.Value == op {
+			return true
+		}
+	}
+	return false
+}
+
+// errorf 在当前位置构造语法错误
+func (p *Parser) errorf(format string, args ...interface{}) error {
+	return &Error{Offset: p.peek().Start, Msg: fmt.Sprintf(format, args...)}
+}
+
+// unexpected 构造意外词法单元错误
+func (p *Parser) unexpected() error {
+	if p.atEnd() {
+		return p.errorf("unexpected end of input")
+	}
+	return p.errorf("unexpected %q", p.peek().Value)
+}
+
+// isNameToken 词法单元能否作为标识符使用
+func (p *Parser) isNameToken(tok lexer.Token) bool {
+	switch tok.Type {
+	case lexer.Identifier, lexer.QuotedIdentifier:
+		return true
+	case lexer.Keyword:
+		return !reserved[tok.Upper()] && !p.reserved[tok.Upper()]
+	}
+	return false
+}
+
+// parseIdent 解析单个标识符
+func (p *Parser) parseIdent() (string, error) {
+	if !p.isNameToken(p.peek()) {
+		return "", p.errorf("expected identifier")
+	}
+	return p.next().Value, nil
+}
+
+// parseName 解析可能带限定的名称，allowStar为true时最后一部分可以是*
+func (p *Parser) parseName(allowStar bool) (*ast.Name, error) {
+	pos := p.peek().Start
+	first, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	name := &ast.Name{Pos: pos, Parts: []string{first}}
+	for p.peek().IsPunct(".") {
+		p.next()
+		if allowStar && p.isOperator("*") {
+			name.Parts = append(name.Parts, p.next().Value)
+			break
+		}
+		part, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		name.Parts = append(name.Parts, part)
+	}
+	return name, nil
+}
+
+// parseColumnList 解析括号中逗号分隔的列名
+func (p *Parser) parseColumnList() ([]*ast.Name, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var columns []*ast.Name
+	for {
+		column, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return columns, p.expectPunct(")")
+}
+
+// parseAlias 解析可选的别名，AS之后允许任意词或字符串
+func (p *Parser) parseAlias() (ast.Keyword, string, error) {
+	if as, ok := p.acceptKeyword("AS"); ok {
+		tok := p.peek()
+		if tok.Type == lexer.Identifier || tok.Type == lexer.QuotedIdentifier ||
+			tok.Type == lexer.Keyword || tok.Type == lexer.String {
+			return as, p.next().Value, nil
+		}
+		return "", "", p.errorf("expected alias")
+	}
+	if p.isNameToken(p.peek()) && !aliasStops[p.peek().Upper()] {
+		return "", p.next().Value, nil
+	}
+	return "", "", nil
+}
+
+// joinTokens 按原始间隔拼接词法单元，原文中有空白或注释处以单个空格分隔
+func joinTokens(tokens []lexer.Token) string {
+	var result strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && tok.Start > tokens[i-1].End {
+			result.WriteString(" ")
+		}
+		result.WriteString(tok.Value)
+	}
+	return result.String()
+}
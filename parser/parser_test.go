@@ -0,0 +1,687 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+func TestParseSelect(t *testing.T) {
+	stmts, err := Parse("select u.id, count(*) as total from users u left join orders o on u.id = o.user_id where u.age > 25 group by u.id having count(*) > 1 order by total desc limit 10")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 1 {
+		t.Fatalf("Expected 1 statement, got %d", len(stmts))
+	}
+
+	stmt, ok := stmts[0].(*ast.SelectStmt)
+	if !ok {
+		t.Fatalf("Expected *ast.SelectStmt, got %T", stmts[0])
+	}
+	if len(stmt.Columns) != 2 {
+		t.Errorf("Expected 2 columns, got %d", len(stmt.Columns))
+	}
+	if stmt.Columns[1].Alias != "total" || !stmt.Columns[1].As.Is("AS") {
+		t.Errorf("Expected alias total, got %q", stmt.Columns[1].Alias)
+	}
+	if _, ok := stmt.Columns[1].Expr.(*ast.FuncCall); !ok {
+		t.Errorf("Expected *ast.FuncCall, got %T", stmt.Columns[1].Expr)
+	}
+
+	join, ok := stmt.From[0].(*ast.JoinExpr)
+	if !ok {
+		t.Fatalf("Expected *ast.JoinExpr, got %T", stmt.From[0])
+	}
+	if join.Type.Upper() != "LEFT JOIN" {
+		t.Errorf("Expected LEFT JOIN, got %q", join.Type)
+	}
+	if right := join.Right.(*ast.TableName); right.Name.String() != "orders" || right.Alias != "o" {
+		t.Errorf("Unexpected join target %q %q", right.Name.String(), right.Alias)
+	}
+	if join.Condition == nil || join.Condition.On == nil {
+		t.Errorf("Expected ON condition")
+	}
+
+	if where, ok := stmt.Where.(*ast.BinaryExpr); !ok || where.Op != ">" {
+		t.Errorf("Unexpected WHERE expression %#v", stmt.Where)
+	}
+	if len(stmt.GroupBy) != 1 || stmt.Having == nil {
+		t.Errorf("Expected GROUP BY and HAVING")
+	}
+	if len(stmt.OrderBy) != 1 || !stmt.OrderBy[0].Direction.Is("DESC") {
+		t.Errorf("Expected ORDER BY total DESC")
+	}
+	if stmt.Limit == nil || stmt.Limit.Count.(*ast.Literal).Value != "10" {
+		t.Errorf("Expected LIMIT 10")
+	}
+}
+
+func TestParseExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected func(ast.Expr) bool
+	}{
+		{
+			name:  "AND binds tighter than OR",
+			input: "select 1 from t where a = 1 or b = 2 and c = 3",
+			expected: func(e ast.Expr) bool {
+				or, ok := e.(*ast.BinaryExpr)
+				return ok && or.Op == "or" && or.Right.(*ast.BinaryExpr).Op == "and"
+			},
+		},
+		{
+			name:  "BETWEEN keeps its AND",
+			input: "select 1 from t where a between 1 and 2 and b = 3",
+			expected: func(e ast.Expr) bool {
+				and, ok := e.(*ast.BinaryExpr)
+				_, between := and.Left.(*ast.BetweenExpr)
+				return ok && and.Op == "and" && between
+			},
+		},
+		{
+			name:  "IN subquery",
+			input: "select 1 from t where id not in (select id from u)",
+			expected: func(e ast.Expr) bool {
+				in, ok := e.(*ast.InExpr)
+				return ok && in.Op.Is("NOT IN") && in.Subquery != nil
+			},
+		},
+		{
+			name:  "NOT EXISTS",
+			input: "select 1 from t where not exists (select 1 from u)",
+			expected: func(e ast.Expr) bool {
+				not, ok := e.(*ast.UnaryExpr)
+				_, exists := not.Expr.(*ast.ExistsExpr)
+				return ok && exists
+			},
+		},
+		{
+			name:  "Unparsed function arguments are kept verbatim",
+			input: "select 1 from t where extract(year from created_at) = 2024",
+			expected: func(e ast.Expr) bool {
+				cmp := e.(*ast.BinaryExpr)
+				call := cmp.Left.(*ast.FuncCall)
+				raw, ok := call.Args[0].(*ast.RawExpr)
+				return ok && raw.Text == "year from created_at"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			where := stmts[0].(*ast.SelectStmt).Where
+			if !tt.expected(where) {
+				t.Errorf("Unexpected expression tree %#v", where)
+			}
+		})
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	stmts, err := Parse("with recursive tree (id, parent) as (select id, parent from nodes), leaves as not materialized (select id from tree) select id from leaves")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	with, ok := stmts[0].(*ast.WithStmt)
+	if !ok {
+		t.Fatalf("Expected *ast.WithStmt, got %T", stmts[0])
+	}
+	if !with.Recursive.Is("RECURSIVE") || len(with.CTEs) != 2 {
+		t.Fatalf("Expected 2 recursive CTEs, got %d", len(with.CTEs))
+	}
+	if tree := with.CTEs[0]; tree.Name.String() != "tree" || len(tree.Columns) != 2 {
+		t.Errorf("Unexpected CTE %q with %d columns", tree.Name.String(), len(tree.Columns))
+	}
+	if leaves := with.CTEs[1]; leaves.Materialized.Upper() != "NOT MATERIALIZED" {
+		t.Errorf("Expected NOT MATERIALIZED, got %q", leaves.Materialized)
+	}
+	if _, ok := with.Stmt.(*ast.SelectStmt); !ok {
+		t.Errorf("Expected *ast.SelectStmt, got %T", with.Stmt)
+	}
+}
+
+func TestParseSetOperations(t *testing.T) {
+	stmts, err := Parse("select a from t union all select b from u intersect select c from v order by 1 limit 5")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	union, ok := stmts[0].(*ast.SetOpStmt)
+	if !ok {
+		t.Fatalf("Expected *ast.SetOpStmt, got %T", stmts[0])
+	}
+	if union.Op.Upper() != "UNION ALL" {
+		t.Errorf("Expected UNION ALL at the top, got %q", union.Op)
+	}
+	if intersect, ok := union.Right.(*ast.SetOpStmt); !ok || !intersect.Op.Is("INTERSECT") {
+		t.Errorf("Expected INTERSECT to bind tighter than UNION, got %#v", union.Right)
+	}
+	if len(union.OrderBy) != 1 || union.Limit == nil {
+		t.Errorf("Expected ORDER BY and LIMIT attached to the compound query")
+	}
+	if last := union.Right.(*ast.SetOpStmt).Right.(*ast.SelectStmt); last.OrderBy != nil || last.Limit != nil {
+		t.Errorf("Expected last SELECT without ORDER BY and LIMIT")
+	}
+}
+
+func TestParseJoins(t *testing.T) {
+	stmts, err := Parse("select * from a natural left outer join b cross join lateral generate_series(1, 3) as g(n) join (c join d using (id)) on true")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	outer := stmts[0].(*ast.SelectStmt).From[0].(*ast.JoinExpr)
+	paren, ok := outer.Right.(*ast.ParenTable)
+	if !ok {
+		t.Fatalf("Expected *ast.ParenTable, got %T", outer.Right)
+	}
+	if inner := paren.Table.(*ast.JoinExpr); len(inner.Condition.Using) != 1 {
+		t.Errorf("Expected USING (id) inside parentheses")
+	}
+
+	cross := outer.Left.(*ast.JoinExpr)
+	fn, ok := cross.Right.(*ast.FuncTable)
+	if !ok || !fn.Lateral.Is("LATERAL") || fn.Alias != "g" || len(fn.Columns) != 1 {
+		t.Errorf("Unexpected LATERAL function table %#v", cross.Right)
+	}
+	if natural := cross.Left.(*ast.JoinExpr); natural.Type.Upper() != "NATURAL LEFT OUTER JOIN" {
+		t.Errorf("Expected NATURAL LEFT OUTER JOIN, got %q", natural.Type)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	stmts, err := Parse("select sum(x) over (w order by b rows between 2 preceding and current row), rank() over w from t window w as (partition by a)")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	stmt := stmts[0].(*ast.SelectStmt)
+
+	spec := stmt.Columns[0].Expr.(*ast.FuncCall).Over.Spec
+	if spec == nil || spec.Name.String() != "w" || len(spec.OrderBy) != 1 {
+		t.Fatalf("Unexpected window spec %#v", spec)
+	}
+	if frame := spec.Frame; frame == nil || !frame.Units.Is("ROWS") || frame.Start.Offset == nil || frame.End.Bound.Upper() != "CURRENT ROW" {
+		t.Errorf("Unexpected window frame %#v", spec.Frame)
+	}
+	if over := stmt.Columns[1].Expr.(*ast.FuncCall).Over; over.Name.String() != "w" {
+		t.Errorf("Expected OVER w, got %#v", over)
+	}
+	if len(stmt.Window) != 1 || len(stmt.Window[0].Spec.PartitionBy) != 1 {
+		t.Errorf("Expected WINDOW w AS (PARTITION BY a)")
+	}
+}
+
+func TestParseDML(t *testing.T) {
+	stmts, err := Parse("INSERT INTO users (name, email) VALUES ('a', 'b'); UPDATE users SET name = 'c' WHERE id = 1; DELETE FROM users WHERE id = 2;")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(stmts))
+	}
+
+	insert := stmts[0].(*ast.InsertStmt)
+	if insert.Table.String() != "users" || len(insert.Columns) != 2 || len(insert.Values) != 1 {
+		t.Errorf("Unexpected INSERT %#v", insert)
+	}
+	update := stmts[1].(*ast.UpdateStmt)
+	if len(update.Set) != 1 || update.Set[0].Column.String() != "name" || update.Where == nil {
+		t.Errorf("Unexpected UPDATE %#v", update)
+	}
+	del := stmts[2].(*ast.DeleteStmt)
+	if del.Table.(*ast.TableName).Name.String() != "users" || del.Where == nil {
+		t.Errorf("Unexpected DELETE %#v", del)
+	}
+}
+
+func TestParseCreateTable(t *testing.T) {
+	stmts, err := Parse("create unlogged table if not exists s.t (id int not null, tags text[], note, constraint pk primary key (id, tags)) with (fillfactor = 70) partition by list (id)")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	stmt := stmts[0].(*ast.CreateTableStmt)
+	if !stmt.Temporary.Is("UNLOGGED") || stmt.IfNotExists == "" || stmt.Name.String() != "s.t" {
+		t.Errorf("Unexpected CREATE TABLE header %#v", stmt)
+	}
+	if len(stmt.Columns) != 3 || stmt.Columns[0].Constraints != "not null" || stmt.Columns[1].Type.Text != "text[]" || stmt.Columns[2].Type != nil {
+		t.Errorf("Unexpected columns %#v", stmt.Columns)
+	}
+	if len(stmt.Constraints) != 1 || stmt.Constraints[0].Text != "constraint pk primary key (id, tags)" {
+		t.Errorf("Unexpected table constraints %#v", stmt.Constraints)
+	}
+	if stmt.Options.Text != "with (fillfactor = 70)" || stmt.PartitionBy.Text != "list (id)" {
+		t.Errorf("Unexpected options %q and partitioning %q", stmt.Options.Text, stmt.PartitionBy.Text)
+	}
+}
+
+func TestParseAlterTable(t *testing.T) {
+	stmts, err := Parse("alter table only users add email text not null, add primary key (id), drop column if exists x, rename to members")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	stmt := stmts[0].(*ast.AlterTableStmt)
+	if stmt.Only == "" || stmt.Name.String() != "users" || len(stmt.Actions) != 4 {
+		t.Fatalf("Unexpected ALTER TABLE %#v", stmt)
+	}
+
+	expected := []struct {
+		action string
+		column string
+		text   string
+	}{
+		{action: "add", column: "email"},
+		{action: "add", text: "primary key (id)"},
+		{action: "drop column if exists", text: "x"},
+		{action: "rename to", text: "members"},
+	}
+	for i, want := range expected {
+		action := stmt.Actions[i]
+		if string(action.Action) != want.action || action.Text != want.text {
+			t.Errorf("Action %d: expected %q %q, got %q %q", i, want.action, want.text, action.Action, action.Text)
+		}
+		if want.column != "" && (action.Column == nil || action.Column.Name.String() != want.column) {
+			t.Errorf("Action %d: expected column definition %q, got %#v", i, want.column, action.Column)
+		}
+	}
+}
+
+func TestParseDDL(t *testing.T) {
+	stmts, err := Parse(`create index ix using hash on t (a, b desc) where a > 0;
+create temp view v as select 1;
+create sequence s start with 1 no cycle;
+create schema authorization joe;
+drop index ix on t;
+drop schema if exists a, b restrict`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 6 {
+		t.Fatalf("Expected 6 statements, got %d", len(stmts))
+	}
+
+	index := stmts[0].(*ast.CreateIndexStmt)
+	if index.Name.String() != "ix" || index.Table.String() != "t" || index.Method != "hash" || index.Columns.Text != "a, b desc" || index.Where == nil {
+		t.Errorf("Unexpected CREATE INDEX %#v", index)
+	}
+	if view := stmts[1].(*ast.CreateViewStmt); !view.Temporary.Is("TEMP") || view.Query == nil {
+		t.Errorf("Unexpected CREATE VIEW %#v", view)
+	}
+	if seq := stmts[2].(*ast.SequenceStmt); !seq.Command.Is("CREATE") || len(seq.Options) != 2 || seq.Options[1].Text != "no cycle" {
+		t.Errorf("Unexpected CREATE SEQUENCE %#v", seq)
+	}
+	if schema := stmts[3].(*ast.CreateSchemaStmt); schema.Name != nil || schema.Options.Text != "authorization joe" {
+		t.Errorf("Unexpected CREATE SCHEMA %#v", schema)
+	}
+	if drop := stmts[4].(*ast.DropStmt); !drop.Object.Is("INDEX") || drop.Table.String() != "t" {
+		t.Errorf("Unexpected DROP INDEX %#v", drop)
+	}
+	if drop := stmts[5].(*ast.DropStmt); drop.IfExists == "" || len(drop.Names) != 2 || !drop.Behavior.Is("RESTRICT") {
+		t.Errorf("Unexpected DROP SCHEMA %#v", drop)
+	}
+}
+
+func TestParseInsert(t *testing.T) {
+	stmts, err := Parse("insert into t values (1, 2), (3, 4); insert into t (a) select a from u; insert into t (select 1); insert into t default values")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if insert := stmts[0].(*ast.InsertStmt); insert.Columns != nil || len(insert.Values) != 2 {
+		t.Errorf("Expected two rows without columns, got %#v", insert)
+	}
+	if insert := stmts[1].(*ast.InsertStmt); len(insert.Columns) != 1 || insert.Query == nil {
+		t.Errorf("Expected INSERT ... SELECT, got %#v", insert)
+	}
+	if insert := stmts[2].(*ast.InsertStmt); insert.Columns != nil {
+		t.Errorf("Expected parenthesized query, got %#v", insert)
+	} else if _, ok := insert.Query.(*ast.ParenStmt); !ok {
+		t.Errorf("Expected *ast.ParenStmt, got %T", insert.Query)
+	}
+	if insert := stmts[3].(*ast.InsertStmt); insert.DefaultValues == "" {
+		t.Errorf("Expected DEFAULT VALUES, got %#v", insert)
+	}
+}
+
+func TestParseUpsert(t *testing.T) {
+	stmts, err := Parse("insert into t (a) values (1) on conflict (lower(a)) where a > 0 do update set a = excluded.a where t.a < 10; insert ignore into t (a) values (1) on duplicate key update a = 2")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	pg := stmts[0].(*ast.InsertStmt).OnConflict
+	if pg == nil || pg.Target.Text != "lower(a)" || !pg.Action.Is("DO UPDATE SET") || len(pg.Set) != 1 || pg.Where == nil {
+		t.Errorf("Unexpected ON CONFLICT %#v", pg)
+	}
+	mysql := stmts[1].(*ast.InsertStmt)
+	if !mysql.Insert.Is("INSERT IGNORE") || mysql.OnConflict == nil || !mysql.OnConflict.Clause.Is("ON DUPLICATE KEY UPDATE") {
+		t.Errorf("Unexpected MySQL upsert %#v", mysql)
+	}
+}
+
+func TestParseReturning(t *testing.T) {
+	stmts, err := Parse("update t set a = 1 output inserted.a into log (a) where id = 1; delete from t output deleted.id where id = 2; delete from t where id = 3 returning id, a as x")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	update := stmts[0].(*ast.UpdateStmt)
+	if update.Output == nil || update.Output.Target.String() != "log" || len(update.Output.TargetNames) != 1 || update.Where == nil {
+		t.Errorf("Unexpected OUTPUT %#v", update.Output)
+	}
+	// OUTPUT不能作为表的隐式别名
+	if del := stmts[1].(*ast.DeleteStmt); del.Table.(*ast.TableName).Alias != "" || del.Output == nil || del.Where == nil {
+		t.Errorf("Unexpected DELETE ... OUTPUT %#v", del)
+	}
+	if del := stmts[2].(*ast.DeleteStmt); del.Returning == nil || len(del.Returning.Columns) != 2 || del.Returning.Columns[1].Alias != "x" {
+		t.Errorf("Unexpected RETURNING %#v", del.Returning)
+	}
+}
+
+func TestParseMultiTableDML(t *testing.T) {
+	stmts, err := Parse("update a join b on a.id = b.id set a.x = b.x limit 1; delete a.*, b from a, b where a.id = b.id; delete from a from a join b on a.id = b.id; delete from a using b returning a.id")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if update := stmts[0].(*ast.UpdateStmt); update.Limit == nil {
+		t.Errorf("Expected LIMIT on UPDATE %#v", update)
+	} else if _, ok := update.Table.(*ast.JoinExpr); !ok {
+		t.Errorf("Expected joined UPDATE target, got %T", update.Table)
+	}
+	if del := stmts[1].(*ast.DeleteStmt); len(del.Targets) != 2 || del.Targets[0].String() != "a.*" || len(del.From) != 2 || del.Table != nil {
+		t.Errorf("Unexpected multi-table DELETE %#v", del)
+	}
+	if del := stmts[2].(*ast.DeleteStmt); del.Table == nil || len(del.From) != 1 {
+		t.Errorf("Unexpected DELETE ... FROM %#v", del)
+	}
+	if del := stmts[3].(*ast.DeleteStmt); len(del.Using) != 1 || del.Returning == nil {
+		t.Errorf("Unexpected DELETE ... USING %#v", del)
+	}
+}
+
+func TestParseMerge(t *testing.T) {
+	stmts, err := Parse("merge into t using (select * from s) x on t.id = x.id when matched and x.v > 0 then update set v = x.v when not matched by source then delete when not matched then insert values (x.id, x.v) returning t.id")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	merge := stmts[0].(*ast.MergeStmt)
+	if _, ok := merge.Source.(*ast.DerivedTable); !ok || merge.On == nil || merge.Returning == nil {
+		t.Fatalf("Unexpected MERGE %#v", merge)
+	}
+	if len(merge.Whens) != 3 {
+		t.Fatalf("Expected 3 WHEN branches, got %d", len(merge.Whens))
+	}
+	if when := merge.Whens[0]; when.Cond == nil || !when.Action.Is("UPDATE SET") || len(when.Set) != 1 {
+		t.Errorf("Unexpected WHEN MATCHED %#v", when)
+	}
+	if when := merge.Whens[1]; !when.Match.Is("NOT MATCHED BY SOURCE") || !when.Action.Is("DELETE") {
+		t.Errorf("Unexpected WHEN NOT MATCHED BY SOURCE %#v", when)
+	}
+	if when := merge.Whens[2]; when.Columns != nil || len(when.Values) != 2 {
+		t.Errorf("Unexpected WHEN NOT MATCHED %#v", when)
+	}
+}
+
+func TestParseWithConfig(t *testing.T) {
+	tokens, err := lexer.TokenizeWithConfig("select a sample from t; sample 10", &lexer.Config{Keywords: []string{"SAMPLE"}})
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	cfg := &Config{
+		Reserved: []string{"SAMPLE"},
+		Statements: map[string]StatementParser{
+			"SAMPLE": func(p *Parser) (ast.Statement, error) {
+				pos := p.next().Start
+				return &ast.SelectStmt{Pos: pos}, nil
+			},
+		},
+	}
+	_, err = NewWithConfig(tokens, cfg).ParseAll()
+	if perr, ok := err.(*Error); !ok || perr.Offset != 9 {
+		t.Fatalf("Expected reserved SAMPLE to stop the alias at offset 9, got %v", err)
+	}
+
+	tokens, _ = lexer.TokenizeWithConfig("sample; select a sample from t", &lexer.Config{})
+	stmts, err := NewWithConfig(tokens, cfg).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 2 || stmts[0].(*ast.SelectStmt).Pos != 0 {
+		t.Fatalf("Expected the dialect statement parser to handle SAMPLE, got %#v", stmts)
+	}
+	if item := stmts[1].(*ast.SelectStmt).Columns[0]; item.Alias != "sample" {
+		t.Errorf("Expected non-keyword sample to be an alias, got %#v", item)
+	}
+}
+
+func TestParsePostgres(t *testing.T) {
+	tokens, err := lexer.TokenizeWithConfig("select distinct on (a) a::text, arr[1:2], array[1, 2] from t where a ilike 'x'", &lexer.Config{Keywords: []string{"ARRAY", "ILIKE"}})
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts, err := NewWithConfig(tokens, &Config{Reserved: []string{"ARRAY", "ILIKE"}}).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	stmt := stmts[0].(*ast.SelectStmt)
+	if !stmt.Distinct.Is("DISTINCT ON") || len(stmt.DistinctOn) != 1 {
+		t.Errorf("Unexpected DISTINCT ON %#v", stmt)
+	}
+	if cast, ok := stmt.Columns[0].Expr.(*ast.CastExpr); !ok || cast.Cast != "" || cast.As != "::" || cast.Type.Text != "text" {
+		t.Errorf("Expected :: cast, got %#v", stmt.Columns[0].Expr)
+	}
+	if sub, ok := stmt.Columns[1].Expr.(*ast.SubscriptExpr); !ok || sub.Index != "1:2" {
+		t.Errorf("Expected subscript, got %#v", stmt.Columns[1].Expr)
+	}
+	if array, ok := stmt.Columns[2].Expr.(*ast.ArrayExpr); !ok || len(array.Elems) != 2 {
+		t.Errorf("Expected array constructor, got %#v", stmt.Columns[2].Expr)
+	}
+	if where, ok := stmt.Where.(*ast.BinaryExpr); !ok || where.Op != "ilike" {
+		t.Errorf("Expected ILIKE, got %#v", stmt.Where)
+	}
+}
+
+func TestParseMySQL(t *testing.T) {
+	tokens, err := lexer.TokenizeWithConfig("select * from t x ignore key for group by (a, b) use index () limit 5, 10", &lexer.Config{Keywords: []string{"FOR", "USE"}})
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts, err := NewWithConfig(tokens, &Config{Reserved: []string{"USE"}}).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	stmt := stmts[0].(*ast.SelectStmt)
+	table := stmt.From[0].(*ast.TableName)
+	if table.Alias != "x" || len(table.Hints) != 2 {
+		t.Fatalf("Unexpected table %#v", table)
+	}
+	if hint := table.Hints[0]; !hint.Hint.Is("IGNORE KEY FOR GROUP BY") || hint.Indexes.Text != "a, b" {
+		t.Errorf("Unexpected hint %#v", hint)
+	}
+	if hint := table.Hints[1]; !hint.Hint.Is("USE INDEX") || hint.Indexes != nil {
+		t.Errorf("Unexpected empty hint %#v", hint)
+	}
+	if limit := stmt.Limit; !limit.Comma || limit.Offset.(*ast.Literal).Value != "5" || limit.Count.(*ast.Literal).Value != "10" {
+		t.Errorf("Unexpected LIMIT %#v", limit)
+	}
+}
+
+func TestParseTSQL(t *testing.T) {
+	cfg := &lexer.Config{IdentifierQuotes: "[", Placeholders: "@", Operators: []string{"+="}, Keywords: []string{"DECLARE", "TOP", "PERCENT"}}
+	tokens, err := lexer.TokenizeWithConfig("declare @a as int = 1; set @a += 2; select top 5 percent [id] from t order by id offset 1 row fetch first 3 rows with ties", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts, err := NewWithConfig(tokens, &Config{
+		Reserved: []string{"TOP", "PERCENT"},
+		Statements: map[string]StatementParser{
+			"DECLARE": (*Parser).ParseDeclare,
+			"SET":     (*Parser).ParseSet,
+		},
+	}).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(stmts))
+	}
+	declare := stmts[0].(*ast.DeclareStmt)
+	if decl := declare.Vars[0]; decl.Name != "@a" || decl.As != "as" || decl.Type.Text != "int" || decl.Value.(*ast.Literal).Value != "1" {
+		t.Errorf("Unexpected declaration %#v", decl)
+	}
+	if set := stmts[1].(*ast.SetStmt); set.Name != "@a" || set.Op != "+=" || set.Value.(*ast.Literal).Value != "2" {
+		t.Errorf("Unexpected SET %#v", set)
+	}
+	stmt := stmts[2].(*ast.SelectStmt)
+	if top := stmt.Top; top == nil || top.Count.(*ast.Literal).Value != "5" || top.Percent != "percent" {
+		t.Errorf("Unexpected TOP %#v", top)
+	}
+	if limit := stmt.Limit; limit.OffsetRows != "row" || !limit.Fetch.Is("FETCH FIRST") ||
+		limit.Count.(*ast.Literal).Value != "3" || !limit.FetchRows.Is("ROWS WITH TIES") {
+		t.Errorf("Unexpected FETCH %#v", limit)
+	}
+
+	tokens, err = lexer.TokenizeWithConfig("insert into t (a) output inserted.id into @ids values (1)", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts, err = New(tokens).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if output := stmts[0].(*ast.InsertStmt).Output; output == nil || output.Target.String() != "@ids" {
+		t.Errorf("Unexpected OUTPUT INTO target %#v", output)
+	}
+}
+
+func TestParseOracle(t *testing.T) {
+	cfg := &lexer.Config{Placeholders: ":", Keywords: []string{"BEGIN", "CONNECT", "ELSIF", "EXCEPTION", "LOOP", "MINUS", "PRIOR", "START", "WHILE"}}
+	parse := func(sql string) []ast.Statement {
+		t.Helper()
+		tokens, err := lexer.TokenizeWithConfig(sql, cfg)
+		if err != nil {
+			t.Fatalf("Tokenize failed: %v", err)
+		}
+		stmts, err := NewWithConfig(tokens, &Config{
+			Reserved:   []string{"CONNECT", "MINUS", "START"},
+			Statements: map[string]StatementParser{"BEGIN": (*Parser).ParseBlock},
+		}).ParseAll()
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		return stmts
+	}
+
+	stmt := parse("select id from emp e, dept d where e.dept_id = d.id(+) connect by prior id = mgr start with mgr is null")[0].(*ast.SelectStmt)
+	if cond := stmt.Where.(*ast.BinaryExpr); cond.Right.(*ast.OuterJoinExpr).Expr.(*ast.Name).String() != "d.id" {
+		t.Errorf("Unexpected outer join marker %#v", cond.Right)
+	}
+	if h := stmt.Hierarchy; h == nil || h.StartFirst || h.StartWith == nil || h.Cond.(*ast.BinaryExpr).Left.(*ast.UnaryExpr).Op != "prior" {
+		t.Errorf("Unexpected hierarchy %#v", h)
+	}
+
+	if op := parse("select a from t minus select a from u")[0].(*ast.SetOpStmt); op.Op != "minus" {
+		t.Errorf("Unexpected set operator %q", op.Op)
+	}
+
+	block := parse("begin select count(*) into n from t; x := x + 1; exception when others then null; end main")[0].(*ast.BlockStmt)
+	if len(block.Body) != 2 || block.Label != "main" || len(block.Handlers) != 1 {
+		t.Fatalf("Unexpected block %#v", block)
+	}
+	if sel, ok := block.Body[0].(*ast.SelectStmt); !ok || len(sel.Into) != 1 {
+		t.Errorf("Expected SELECT INTO, got %#v", block.Body[0])
+	}
+	if raw, ok := block.Body[1].(*ast.RawStmt); !ok || raw.Text != "x := x + 1" {
+		t.Errorf("Expected raw assignment, got %#v", block.Body[1])
+	}
+	if handler := block.Handlers[0]; handler.Names.Text != "others" || len(handler.Body) != 1 {
+		t.Errorf("Unexpected handler %#v", handler)
+	}
+
+	block = parse("begin if x > 0 then y := 1; elsif x < 0 then null; else y := 0; end if; " +
+		"while x < 10 loop x := x + 1; end loop; case x when 1 then null; end case; end")[0].(*ast.BlockStmt)
+	if len(block.Body) != 3 {
+		t.Fatalf("Unexpected block body %#v", block.Body)
+	}
+	if stmt, ok := block.Body[0].(*ast.IfStmt); !ok || len(stmt.Branches) != 2 || stmt.Branches[1].Cond.Text != "x < 0" || len(stmt.ElseBody) != 1 {
+		t.Errorf("Unexpected IF statement %#v", block.Body[0])
+	}
+	if stmt, ok := block.Body[1].(*ast.LoopStmt); !ok || stmt.Head.Text != "while x < 10" || len(stmt.Body) != 1 {
+		t.Errorf("Unexpected loop %#v", block.Body[1])
+	}
+	if stmt, ok := block.Body[2].(*ast.CaseStmt); !ok || stmt.Operand.Text != "x" || len(stmt.Whens) != 1 {
+		t.Errorf("Unexpected CASE statement %#v", block.Body[2])
+	}
+}
+
+func TestParseSQLite(t *testing.T) {
+	cfg := &lexer.Config{Placeholders: "?:@$", Keywords: []string{"ABORT", "ATTACH", "DATABASE", "PRAGMA", "VIRTUAL"}}
+	tokens, err := lexer.TokenizeWithConfig("pragma main.journal_mode = wal; pragma table_info(users); "+
+		"insert or abort into t values (?1, $b); attach database :file as aux; "+
+		"create virtual table docs using fts5(title, body, tokenize = 'porter unicode61')", cfg)
+	if err != nil {
+		t.Fatalf("Tokenize failed: %v", err)
+	}
+	stmts, err := NewWithConfig(tokens, &Config{Statements: map[string]StatementParser{
+		"PRAGMA": (*Parser).ParsePragma,
+		"ATTACH": (*Parser).ParseAttach,
+	}}).ParseAll()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stmts) != 5 {
+		t.Fatalf("Expected 5 statements, got %d", len(stmts))
+	}
+	if pragma := stmts[0].(*ast.PragmaStmt); pragma.Name.String() != "main.journal_mode" || !pragma.Eq || pragma.Value.Text != "wal" {
+		t.Errorf("Unexpected PRAGMA %#v", pragma)
+	}
+	if pragma := stmts[1].(*ast.PragmaStmt); pragma.Eq || pragma.Value.Text != "users" {
+		t.Errorf("Unexpected PRAGMA call %#v", pragma)
+	}
+	if insert := stmts[2].(*ast.InsertStmt); !insert.Insert.Is("INSERT OR ABORT") {
+		t.Errorf("Unexpected INSERT verb %q", insert.Insert)
+	}
+	if attach := stmts[3].(*ast.AttachStmt); attach.File.(*ast.Placeholder).Value != ":file" || attach.Schema != "aux" {
+		t.Errorf("Unexpected ATTACH %#v", attach)
+	}
+	vtab := stmts[4].(*ast.CreateVirtualTableStmt)
+	if vtab.Module != "fts5" || len(vtab.Args) != 3 || vtab.Args[2].Text != "tokenize = 'porter unicode61'" {
+		t.Errorf("Unexpected virtual table %#v", vtab)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		offset int
+	}{
+		{name: "Missing select list", input: "select from t", offset: 7},
+		{name: "Unbalanced parenthesis", input: "select (a from t", offset: 10},
+		{name: "Trailing garbage", input: "select a from t t2 t3", offset: 19},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Expected error to occur, but no error happened")
+			}
+			perr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("Expected *Error, got %T", err)
+			}
+			if perr.Offset != tt.offset {
+				t.Errorf("Expected offset %d, got %d (%v)", tt.offset, perr.Offset, err)
+			}
+		})
+	}
+}
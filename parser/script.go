@@ -0,0 +1,305 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+// ParseDeclare parses DECLARE @name [AS] type [= value] [, ...]; dialects
+// register it as a StatementParser
+func (p *Parser) ParseDeclare() (ast.Statement, error) {
+	pos := p.peek().Start
+	stmt := &ast.DeclareStmt{Pos: pos, Declare: ast.Keyword(p.next().Value)}
+	for {
+		decl, err := p.parseVarDecl()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Vars = append(stmt.Vars, decl)
+		if !p.acceptPunct(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// parseVarDecl 解析DECLARE中的单个变量声明
+func (p *Parser) parseVarDecl() (*ast.VarDecl, error) {
+	if !p.isVariable() {
+		return nil, p.errorf("expected variable")
+	}
+	tok := p.next()
+	decl := &ast.VarDecl{Pos: tok.Start, Name: tok.Value}
+	decl.As, _ = p.acceptKeyword("AS")
+	dataType, err := p.parseDataType()
+	if err != nil {
+		return nil, err
+	}
+	decl.Type = dataType
+	if p.isOperator("=") {
+		p.next()
+		if decl.Value, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return decl, nil
+}
+
+// ParseSet parses SET @var = value or SET followed by a session option;
+// dialects register it as a StatementParser
+func (p *Parser) ParseSet() (ast.Statement, error) {
+	pos := p.peek().Start
+	stmt := &ast.SetStmt{Pos: pos, Set: ast.Keyword(p.next().Value)}
+	if !p.isVariable() {
+		// 会话选项原样保留，如 SET NOCOUNT ON
+		if stmt.Option = p.parseRawUntil(func() bool { return false }); stmt.Option == nil {
+			return nil, p.errorf("expected variable or option")
+		}
+		return stmt, nil
+	}
+	stmt.Name = p.next().Value
+	if p.peek().Type != lexer.Operator || !strings.HasSuffix(p.peek().Value, "=") {
+		return nil, p.errorf("expected assignment operator")
+	}
+	stmt.Op = p.next().Value
+	value, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Value = value
+	return stmt, nil
+}
+
+// isVariable 当前词法单元是否为 @name 形式的变量
+func (p *Parser) isVariable() bool {
+	tok := p.peek()
+	return tok.Type == lexer.Placeholder && strings.HasPrefix(tok.Value, "@")
+}
+
+// blockStatements 过程块中按语法解析的语句开头，其余语句原样保留
+var blockStatements = []string{"SELECT", "WITH", "INSERT", "UPDATE", "DELETE", "MERGE"}
+
+// ParseBlock parses a procedural block such as a PL/SQL anonymous block:
+// [DECLARE decls] BEGIN stmts [EXCEPTION handlers] END [label]; dialects
+// register it as a StatementParser
+func (p *Parser) ParseBlock() (ast.Statement, error) {
+	block := &ast.BlockStmt{Pos: p.peek().Start}
+	if kw, ok := p.acceptKeyword("DECLARE"); ok {
+		block.Declare = kw
+		for !p.atEnd() && !p.isKeyword("BEGIN") {
+			decl := p.parseRawUntil(func() bool { return p.isKeyword("BEGIN") })
+			if decl == nil {
+				return nil, p.unexpected()
+			}
+			block.Decls = append(block.Decls, decl)
+			if err := p.expectPunct(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var err error
+	block.BeginPos = p.peek().Start
+	if block.Begin, err = p.expectKeyword("BEGIN"); err != nil {
+		return nil, err
+	}
+	if block.Body, err = p.parseBlockBody("EXCEPTION", "END"); err != nil {
+		return nil, err
+	}
+	if p.isKeyword("EXCEPTION") {
+		block.ExceptionPos = p.peek().Start
+		block.Exception = ast.Keyword(p.next().Value)
+		for p.isKeyword("WHEN") {
+			handler, err := p.parseExceptionHandler()
+			if err != nil {
+				return nil, err
+			}
+			block.Handlers = append(block.Handlers, handler)
+		}
+	}
+	block.EndPos = p.peek().Start
+	if block.End, err = p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	if p.isNameToken(p.peek()) {
+		block.Label = p.next().Value
+	}
+	return block, nil
+}
+
+// parseExceptionHandler 解析 WHEN name [OR name] THEN stmts
+func (p *Parser) parseExceptionHandler() (*ast.ExceptionHandler, error) {
+	tok := p.next()
+	handler := &ast.ExceptionHandler{Pos: tok.Start, When: ast.Keyword(tok.Value)}
+	if handler.Names = p.parseRawUntil(func() bool { return p.isKeyword("THEN") }); handler.Names == nil {
+		return nil, p.errorf("expected exception name")
+	}
+	var err error
+	if handler.Then, err = p.expectKeyword("THEN"); err != nil {
+		return nil, err
+	}
+	if handler.Body, err = p.parseBlockBody("WHEN", "END"); err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+// parseBlockBody 解析以分号结尾的语句序列，直到遇到给定关键字
+func (p *Parser) parseBlockBody(stop ...string) ([]ast.Statement, error) {
+	var stmts []ast.Statement
+	for !p.isKeyword(stop...) {
+		if p.atEnd() {
+			return nil, p.errorf("expected %s", strings.Join(stop, " or "))
+		}
+		stmt, err := p.parseBlockStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		if err := p.expectPunct(";"); err != nil {
+			return nil, err
+		}
+	}
+	return stmts, nil
+}
+
+// parseBlockStatement 解析过程块中的一条语句：嵌套块、IF、CASE与循环语句、可解析的SQL语句，
+// 或原样保留的其他语句
+func (p *Parser) parseBlockStatement() (ast.Statement, error) {
+	if p.isKeyword("DECLARE", "BEGIN") {
+		return p.ParseBlock()
+	}
+	start := p.pos
+	var parse func() (ast.Statement, error)
+	switch {
+	case p.isKeyword("IF"):
+		parse = p.parseIf
+	case p.isKeyword("CASE"):
+		parse = p.parseCaseStatement
+	case p.isKeyword("LOOP", "WHILE", "FOR"):
+		parse = p.parseLoop
+	case p.isKeyword(blockStatements...):
+		parse = p.ParseStatement
+	}
+	if parse != nil {
+		if stmt, err := parse(); err == nil && p.peek().IsPunct(";") {
+			return stmt, nil
+		}
+		p.pos = start
+	}
+	// 跳到块外层的分号，IF、LOOP、CASE与BEGIN在对应的END处结束
+	depth := 0
+	prev := ""
+	for !p.atEnd() && (depth > 0 || !p.peek().IsPunct(";")) {
+		tok := p.next()
+		switch {
+		case tok.IsPunct("("):
+			depth++
+		case tok.IsPunct(")"):
+			depth--
+		case tok.IsKeyword("BEGIN", "CASE", "IF", "LOOP") && prev != "END":
+			depth++
+		case tok.IsKeyword("END"):
+			depth--
+		}
+		prev = tok.Upper()
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected statement")
+	}
+	return &ast.RawStmt{Pos: p.tokens[start].Start, Text: joinTokens(p.tokens[start:p.pos])}, nil
+}
+
+// parseIf 解析 IF cond THEN stmts [ELSIF cond THEN stmts]... [ELSE stmts] END IF
+func (p *Parser) parseIf() (ast.Statement, error) {
+	stmt := &ast.IfStmt{Pos: p.peek().Start}
+	for len(stmt.Branches) == 0 || p.isKeyword("ELSIF") {
+		branch, err := p.parseBranch("ELSIF", "ELSE", "END")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Branches = append(stmt.Branches, branch)
+	}
+	var err error
+	if p.isKeyword("ELSE") {
+		stmt.ElsePos = p.peek().Start
+		stmt.Else = ast.Keyword(p.next().Value)
+		if stmt.ElseBody, err = p.parseBlockBody("END"); err != nil {
+			return nil, err
+		}
+	}
+	stmt.EndPos = p.peek().Start
+	if stmt.End, err = p.expectKeyword("END", "IF"); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCaseStatement 解析 CASE [operand] WHEN x THEN stmts ... [ELSE stmts] END CASE
+func (p *Parser) parseCaseStatement() (ast.Statement, error) {
+	stmt := &ast.CaseStmt{Pos: p.peek().Start, Case: ast.Keyword(p.next().Value)}
+	stmt.Operand = p.parseRawUntil(func() bool { return p.isKeyword("WHEN") })
+	for p.isKeyword("WHEN") {
+		branch, err := p.parseBranch("WHEN", "ELSE", "END")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Whens = append(stmt.Whens, branch)
+	}
+	if stmt.Whens == nil {
+		return nil, p.errorf("expected WHEN")
+	}
+	var err error
+	if p.isKeyword("ELSE") {
+		stmt.ElsePos = p.peek().Start
+		stmt.Else = ast.Keyword(p.next().Value)
+		if stmt.ElseBody, err = p.parseBlockBody("END"); err != nil {
+			return nil, err
+		}
+	}
+	stmt.EndPos = p.peek().Start
+	if stmt.End, err = p.expectKeyword("END", "CASE"); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseBranch 解析IF、ELSIF或WHEN开头的 cond THEN stmts 分支，语句序列在给定关键字处结束
+func (p *Parser) parseBranch(stop ...string) (*ast.Branch, error) {
+	tok := p.next()
+	branch := &ast.Branch{Pos: tok.Start, Word: ast.Keyword(tok.Value)}
+	if branch.Cond = p.parseRawUntil(func() bool { return p.isKeyword("THEN") }); branch.Cond == nil {
+		return nil, p.errorf("expected condition")
+	}
+	var err error
+	if branch.Then, err = p.expectKeyword("THEN"); err != nil {
+		return nil, err
+	}
+	if branch.Body, err = p.parseBlockBody(stop...); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// parseLoop 解析 [WHILE cond | FOR iterator] LOOP stmts END LOOP [label]
+func (p *Parser) parseLoop() (ast.Statement, error) {
+	stmt := &ast.LoopStmt{Pos: p.peek().Start}
+	if p.isKeyword("WHILE", "FOR") {
+		stmt.Head = p.parseRawUntil(func() bool { return p.isKeyword("LOOP") })
+	}
+	var err error
+	if stmt.Loop, err = p.expectKeyword("LOOP"); err != nil {
+		return nil, err
+	}
+	if stmt.Body, err = p.parseBlockBody("END"); err != nil {
+		return nil, err
+	}
+	stmt.EndPos = p.peek().Start
+	if stmt.End, err = p.expectKeyword("END", "LOOP"); err != nil {
+		return nil, err
+	}
+	if p.isNameToken(p.peek()) {
+		stmt.Label = p.next().Value
+	}
+	return stmt, nil
+}
@@ -0,0 +1,604 @@
+package parser
+
+import (
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// joinTypes 可识别的连接关键字序列，较长的序列在前
+var joinTypes = [][]string{
+	{"INNER", "JOIN"},
+	{"LEFT", "OUTER", "JOIN"},
+	{"LEFT", "JOIN"},
+	{"RIGHT", "OUTER", "JOIN"},
+	{"RIGHT", "JOIN"},
+	{"FULL", "OUTER", "JOIN"},
+	{"FULL", "JOIN"},
+	{"CROSS", "JOIN"},
+	{"CROSS", "APPLY"},
+	{"OUTER", "APPLY"},
+	{"NATURAL", "LEFT", "OUTER", "JOIN"},
+	{"NATURAL", "LEFT", "JOIN"},
+	{"NATURAL", "RIGHT", "OUTER", "JOIN"},
+	{"NATURAL", "RIGHT", "JOIN"},
+	{"NATURAL", "FULL", "OUTER", "JOIN"},
+	{"NATURAL", "FULL", "JOIN"},
+	{"NATURAL", "INNER", "JOIN"},
+	{"NATURAL", "JOIN"},
+	{"STRAIGHT_JOIN"},
+	{"JOIN"},
+}
+
+// parseQuery 解析查询，可以带WITH子句
+func (p *Parser) parseQuery() (ast.Statement, error) {
+	if p.isKeyword("WITH") {
+		return p.parseWith()
+	}
+	return p.parseSetOperation()
+}
+
+// parseSetOperation 解析可能由集合运算组合的查询，其后的ORDER BY/LIMIT作用于整个结果
+func (p *Parser) parseSetOperation() (ast.Statement, error) {
+	query, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := query.(*ast.ParenStmt); ok {
+		return query, nil
+	}
+
+	var orderBy []*ast.OrderItem
+	siblings, _ := p.acceptKeyword("ORDER", "SIBLINGS", "BY")
+	if _, ok := p.acceptKeyword("ORDER", "BY"); ok || siblings != "" {
+		if orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	limit, err := p.parseLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	switch query := query.(type) {
+	case *ast.SelectStmt:
+		query.OrderBy, query.Siblings, query.Limit = orderBy, siblings, limit
+	case *ast.SetOpStmt:
+		if siblings != "" {
+			return nil, &Error{Offset: ast.Pos(orderBy[0]), Msg: "ORDER SIBLINGS BY requires a single query"}
+		}
+		query.OrderBy, query.Limit = orderBy, limit
+	}
+	return query, nil
+}
+
+// parseUnion 解析UNION/EXCEPT/MINUS连接的查询
+func (p *Parser) parseUnion() (ast.Statement, error) {
+	left, err := p.parseIntersect()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptSetOperator("UNION", "EXCEPT", "MINUS")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseIntersect()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.SetOpStmt{Left: left, Op: op, Right: right}
+	}
+}
+
+// parseIntersect 解析INTERSECT连接的查询，优先级高于UNION
+func (p *Parser) parseIntersect() (ast.Statement, error) {
+	left, err := p.parseQueryPrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptSetOperator("INTERSECT")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseQueryPrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.SetOpStmt{Left: left, Op: op, Right: right}
+	}
+}
+
+// acceptSetOperator 若当前为给定集合运算符（可带ALL或DISTINCT）则消耗并返回其原文
+func (p *Parser) acceptSetOperator(words ...string) (ast.Keyword, bool) {
+	for _, word := range words {
+		for _, quantifier := range []string{"ALL", "DISTINCT"} {
+			if kw, ok := p.acceptKeyword(word, quantifier); ok {
+				return kw, true
+			}
+		}
+		if kw, ok := p.acceptKeyword(word); ok {
+			return kw, true
+		}
+	}
+	return "", false
+}
+
+// parseQueryPrimary 解析集合运算的操作数：SELECT或括号中的查询
+func (p *Parser) parseQueryPrimary() (ast.Statement, error) {
+	if !p.peek().IsPunct("(") {
+		return p.parseSelect()
+	}
+	pos := p.next().Start
+	stmt, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	rparen := p.peek().Start
+	return &ast.ParenStmt{Pos: pos, Stmt: stmt, Rparen: rparen}, p.expectPunct(")")
+}
+
+// parseWith 解析 WITH [RECURSIVE] cte, ... 及其后的语句
+func (p *Parser) parseWith() (*ast.WithStmt, error) {
+	stmt := &ast.WithStmt{Pos: p.peek().Start}
+	if _, err := p.expectKeyword("WITH"); err != nil {
+		return nil, err
+	}
+	stmt.Recursive, _ = p.acceptKeyword("RECURSIVE")
+	for {
+		cte, err := p.parseCTE()
+		if err != nil {
+			return nil, err
+		}
+		stmt.CTEs = append(stmt.CTEs, cte)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+
+	var err error
+	if stmt.Stmt, err = p.ParseStatement(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCTE 解析 name [(columns)] AS [[NOT] MATERIALIZED] (query)
+func (p *Parser) parseCTE() (*ast.CTE, error) {
+	name, err := p.parseName(false)
+	if err != nil {
+		return nil, err
+	}
+	cte := &ast.CTE{Name: name}
+	if p.peek().IsPunct("(") {
+		if cte.Columns, err = p.parseColumnList(); err != nil {
+			return nil, err
+		}
+	}
+	if cte.As, err = p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	if kw, ok := p.acceptKeyword("MATERIALIZED"); ok {
+		cte.Materialized = kw
+	} else if kw, ok := p.acceptKeyword("NOT", "MATERIALIZED"); ok {
+		cte.Materialized = kw
+	}
+	if cte.Subquery, err = p.parseSubquery(); err != nil {
+		return nil, err
+	}
+	return cte, nil
+}
+
+// parseSelect 解析SELECT语句，ORDER BY/LIMIT由parseSetOperation解析
+func (p *Parser) parseSelect() (*ast.SelectStmt, error) {
+	stmt := &ast.SelectStmt{Pos: p.peek().Start}
+	if _, err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if kw, ok := p.acceptKeyword("DISTINCT", "ON"); ok {
+		stmt.Distinct = kw
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		exprs, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		stmt.DistinctOn = exprs
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	} else if kw, ok := p.acceptKeyword("DISTINCT"); ok {
+		stmt.Distinct = kw
+	} else if kw, ok := p.acceptKeyword("ALL"); ok {
+		stmt.Distinct = kw
+	}
+	if p.isKeyword("TOP") {
+		top, err := p.parseTop()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Top = top
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Columns = append(stmt.Columns, item)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+
+	var err error
+	if _, ok := p.acceptKeyword("INTO"); ok {
+		if stmt.Into, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("FROM"); ok {
+		if stmt.From, err = p.parseTableExprs(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("WHERE"); ok {
+		if stmt.Where, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeywordSeq("START", "WITH") || p.isKeywordSeq("CONNECT", "BY") {
+		if stmt.Hierarchy, err = p.parseHierarchy(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("GROUP", "BY"); ok {
+		if stmt.GroupBy, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("HAVING"); ok {
+		if stmt.Having, err = p.ParseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("WINDOW"); ok {
+		if stmt.Window, err = p.parseWindowDefs(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseHierarchy 解析层次查询子句 [START WITH cond] CONNECT BY [NOCYCLE] cond，两部分顺序不限
+func (p *Parser) parseHierarchy() (*ast.Hierarchy, error) {
+	clause := &ast.Hierarchy{Pos: p.peek().Start, StartFirst: p.isKeyword("START")}
+	var err error
+	for i := 0; i < 2; i++ {
+		if clause.StartWith == nil {
+			if _, ok := p.acceptKeyword("START", "WITH"); ok {
+				if clause.StartWith, err = p.ParseExpr(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if clause.Cond == nil {
+			if kw, ok := p.acceptKeyword("CONNECT", "BY"); ok {
+				if nocycle, ok := p.acceptKeyword("NOCYCLE"); ok {
+					kw += " " + nocycle
+				}
+				clause.ConnectBy = kw
+				if clause.Cond, err = p.ParseExpr(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if clause.Cond == nil {
+		return nil, p.errorf("expected CONNECT BY")
+	}
+	return clause, nil
+}
+
+// parseTop 解析 TOP (n) [PERCENT] [WITH TIES]，不带括号时数量为单个字面量或变量
+func (p *Parser) parseTop() (*ast.Top, error) {
+	tok := p.next()
+	top := &ast.Top{Pos: tok.Start, Top: ast.Keyword(tok.Value)}
+	var err error
+	if p.peek().IsPunct("(") {
+		top.Count, err = p.parseParen()
+	} else {
+		top.Count, err = p.parsePrimary()
+	}
+	if err != nil {
+		return nil, err
+	}
+	top.Percent, _ = p.acceptKeyword("PERCENT")
+	if p.isKeyword("WITH") && p.peekAt(1).Upper() == "TIES" {
+		top.WithTies = ast.Keyword(p.next().Value + " " + p.next().Value)
+	}
+	return top, nil
+}
+
+// parseSelectItem 解析选择列表中的一项
+func (p *Parser) parseSelectItem() (*ast.SelectItem, error) {
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+	as, alias, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.SelectItem{Expr: expr, As: as, Alias: alias}, nil
+}
+
+// parseOrderBy 解析ORDER BY列表
+func (p *Parser) parseOrderBy() ([]*ast.OrderItem, error) {
+	var items []*ast.OrderItem
+	for {
+		expr, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		item := &ast.OrderItem{Expr: expr}
+		if kw, ok := p.acceptKeyword("ASC"); ok {
+			item.Direction = kw
+		} else if kw, ok := p.acceptKeyword("DESC"); ok {
+			item.Direction = kw
+		}
+		if kw, ok := p.acceptKeyword("NULLS", "FIRST"); ok {
+			item.Nulls = kw
+		} else if kw, ok := p.acceptKeyword("NULLS", "LAST"); ok {
+			item.Nulls = kw
+		}
+		items = append(items, item)
+		if !p.acceptPunct(",") {
+			return items, nil
+		}
+	}
+}
+
+// parseLimit 解析LIMIT/OFFSET子句
+func (p *Parser) parseLimit() (*ast.Limit, error) {
+	var limit *ast.Limit
+	if _, ok := p.acceptKeyword("LIMIT"); ok {
+		count, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		limit = &ast.Limit{Count: count}
+		if p.acceptPunct(",") {
+			// MySQL的 LIMIT offset, count
+			if limit.Count, err = p.ParseExpr(); err != nil {
+				return nil, err
+			}
+			limit.Offset, limit.Comma = count, true
+			return limit, nil
+		}
+	}
+	if _, ok := p.acceptKeyword("OFFSET"); ok {
+		offset, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if limit == nil {
+			limit = &ast.Limit{}
+		}
+		limit.Offset = offset
+		limit.OffsetRows, _ = p.acceptRows()
+	}
+	if p.isKeyword("FETCH") && (p.peekAt(1).IsKeyword("FIRST") || p.peekAt(1).Upper() == "NEXT") {
+		if limit == nil {
+			limit = &ast.Limit{}
+		}
+		if err := p.parseFetch(limit); err != nil {
+			return nil, err
+		}
+	}
+	return limit, nil
+}
+
+// parseFetch 解析 FETCH {FIRST | NEXT} [n [PERCENT]] {ROW | ROWS} {ONLY | WITH TIES}
+func (p *Parser) parseFetch(limit *ast.Limit) error {
+	limit.Fetch = ast.Keyword(p.next().Value + " " + p.next().Value)
+	if !p.isKeyword("ROW", "ROWS") && p.peek().Upper() != "PERCENT" {
+		count, err := p.ParseExpr()
+		if err != nil {
+			return err
+		}
+		limit.Count = count
+	}
+	start := p.pos
+	if p.peek().Upper() == "PERCENT" {
+		p.next()
+	}
+	if _, ok := p.acceptRows(); !ok {
+		return p.errorf("expected ROW or ROWS")
+	}
+	if _, ok := p.acceptKeyword("ONLY"); !ok {
+		if !p.isKeyword("WITH") || p.peekAt(1).Upper() != "TIES" {
+			return p.errorf("expected ONLY or WITH TIES")
+		}
+		p.pos += 2
+	}
+	limit.FetchRows = ast.Keyword(joinTokens(p.tokens[start:p.pos]))
+	return nil
+}
+
+// acceptRows 若当前为ROW或ROWS则消耗并返回其原文
+func (p *Parser) acceptRows() (ast.Keyword, bool) {
+	if kw, ok := p.acceptKeyword("ROWS"); ok {
+		return kw, true
+	}
+	return p.acceptKeyword("ROW")
+}
+
+// parseTableExprs 解析FROM子句中逗号分隔的表
+func (p *Parser) parseTableExprs() ([]ast.TableExpr, error) {
+	var tables []ast.TableExpr
+	for {
+		table, err := p.parseJoinedTable()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		if !p.acceptPunct(",") {
+			return tables, nil
+		}
+	}
+}
+
+// parseJoinedTable 解析表及其后的连接
+func (p *Parser) parseJoinedTable() (ast.TableExpr, error) {
+	left, err := p.parseTablePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		joinType, ok := p.acceptJoinType()
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTablePrimary()
+		if err != nil {
+			return nil, err
+		}
+		join := &ast.JoinExpr{Left: left, Type: joinType, Right: right}
+		if kw, ok := p.acceptKeyword("ON"); ok {
+			on, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			join.Condition = &ast.JoinCondition{Keyword: kw, On: on}
+		} else if kw, ok := p.acceptKeyword("USING"); ok {
+			columns, err := p.parseColumnList()
+			if err != nil {
+				return nil, err
+			}
+			join.Condition = &ast.JoinCondition{Keyword: kw, Using: columns}
+		}
+		left = join
+	}
+}
+
+// acceptJoinType 若当前位置为连接关键字则消耗并返回其原文
+func (p *Parser) acceptJoinType() (ast.Keyword, bool) {
+	for _, words := range joinTypes {
+		if kw, ok := p.acceptKeyword(words...); ok {
+			return kw, true
+		}
+	}
+	return "", false
+}
+
+// parseTablePrimary 解析单个表、派生表、表函数或括号中的连接
+func (p *Parser) parseTablePrimary() (ast.TableExpr, error) {
+	lateral, _ := p.acceptKeyword("LATERAL")
+	if p.peek().IsPunct("(") {
+		if lateral == "" && !p.isQueryInParens() {
+			return p.parseParenTable()
+		}
+		subquery, err := p.parseSubquery()
+		if err != nil {
+			return nil, err
+		}
+		table := &ast.DerivedTable{Lateral: lateral, Subquery: subquery}
+		if table.As, table.Alias, table.Columns, err = p.parseTableAlias(); err != nil {
+			return nil, err
+		}
+		return table, nil
+	}
+
+	name, err := p.parseName(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().IsPunct("(") {
+		call, err := p.parseFuncCall(name)
+		if err != nil {
+			return nil, err
+		}
+		table := &ast.FuncTable{Lateral: lateral, Func: call.(*ast.FuncCall)}
+		if table.As, table.Alias, table.Columns, err = p.parseTableAlias(); err != nil {
+			return nil, err
+		}
+		return table, nil
+	}
+	if lateral != "" {
+		return nil, p.errorf("expected subquery or function after LATERAL")
+	}
+	table := &ast.TableName{Name: name}
+	if !p.isIndexHint() {
+		if table.As, table.Alias, err = p.parseAlias(); err != nil {
+			return nil, err
+		}
+	}
+	for p.isIndexHint() {
+		hint, err := p.parseIndexHint()
+		if err != nil {
+			return nil, err
+		}
+		table.Hints = append(table.Hints, hint)
+	}
+	return table, nil
+}
+
+// isIndexHint 当前位置是否为 USE/FORCE/IGNORE INDEX|KEY 索引提示
+func (p *Parser) isIndexHint() bool {
+	return p.isKeyword("USE", "FORCE", "IGNORE") && p.peekAt(1).IsKeyword("INDEX", "KEY")
+}
+
+// parseIndexHint 解析 {USE|FORCE|IGNORE} {INDEX|KEY} [FOR {JOIN|ORDER BY|GROUP BY}] (索引列表)
+func (p *Parser) parseIndexHint() (*ast.IndexHint, error) {
+	start := p.pos
+	hint := &ast.IndexHint{Pos: p.peek().Start}
+	p.pos += 2
+	if _, ok := p.acceptKeyword("FOR"); ok {
+		if _, ok := p.acceptKeyword("ORDER", "BY"); !ok {
+			if _, ok := p.acceptKeyword("GROUP", "BY"); !ok {
+				p.next()
+			}
+		}
+	}
+	hint.Hint = ast.Keyword(joinTokens(p.tokens[start:p.pos]))
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	hint.Indexes = p.parseRawUntil(func() bool { return false })
+	return hint, p.expectPunct(")")
+}
+
+// parseTableAlias 解析派生表或表函数的别名及其后可选的列名列表
+func (p *Parser) parseTableAlias() (ast.Keyword, string, []*ast.Name, error) {
+	as, alias, err := p.parseAlias()
+	if err != nil || alias == "" || !p.peek().IsPunct("(") {
+		return as, alias, nil, err
+	}
+	columns, err := p.parseColumnList()
+	if err != nil {
+		return "", "", nil, err
+	}
+	return as, alias, columns, nil
+}
+
+// isQueryInParens 当前位置的括号（可多层）中是否为查询
+func (p *Parser) isQueryInParens() bool {
+	i := 0
+	for p.peekAt(i).IsPunct("(") {
+		i++
+	}
+	return i > 0 && p.peekAt(i).IsKeyword("SELECT", "WITH")
+}
+
+// parseParenTable 解析括号中的连接
+func (p *Parser) parseParenTable() (*ast.ParenTable, error) {
+	pos := p.next().Start
+	table, err := p.parseJoinedTable()
+	if err != nil {
+		return nil, err
+	}
+	rparen := p.peek().Start
+	return &ast.ParenTable{Pos: pos, Table: table, Rparen: rparen}, p.expectPunct(")")
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// parseOver 解析函数调用之后可选的OVER子句
+func (p *Parser) parseOver(call *ast.FuncCall) (ast.Expr, error) {
+	over, ok := p.acceptKeyword("OVER")
+	if !ok {
+		return call, nil
+	}
+	call.Over = &ast.OverClause{Over: over}
+	if !p.peek().IsPunct("(") {
+		name, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		call.Over.Name = name
+		return call, nil
+	}
+
+	spec, err := p.parseWindowSpec()
+	if err != nil {
+		return nil, err
+	}
+	call.Over.Spec = spec
+	return call, nil
+}
+
+// parseWindowDefs 解析WINDOW子句中逗号分隔的 name AS (spec)
+func (p *Parser) parseWindowDefs() ([]*ast.WindowDef, error) {
+	var defs []*ast.WindowDef
+	for {
+		name, err := p.parseName(false)
+		if err != nil {
+			return nil, err
+		}
+		as, err := p.expectKeyword("AS")
+		if err != nil {
+			return nil, err
+		}
+		spec, err := p.parseWindowSpec()
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, &ast.WindowDef{Name: name, As: as, Spec: spec})
+		if !p.acceptPunct(",") {
+			return defs, nil
+		}
+	}
+}
+
+// parseWindowSpec 解析 ([name] [PARTITION BY ...] [ORDER BY ...] [frame])
+func (p *Parser) parseWindowSpec() (*ast.WindowSpec, error) {
+	spec := &ast.WindowSpec{Pos: p.peek().Start}
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.isNameToken(p.peek()) && !p.isKeyword("PARTITION", "ORDER", "ROWS", "RANGE", "GROUPS") {
+		if spec.Name, err = p.parseName(false); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("PARTITION", "BY"); ok {
+		if spec.PartitionBy, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.acceptKeyword("ORDER", "BY"); ok {
+		if spec.OrderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("ROWS", "RANGE", "GROUPS") {
+		if spec.Frame, err = p.parseWindowFrame(); err != nil {
+			return nil, err
+		}
+	}
+
+	spec.Rparen = p.peek().Start
+	return spec, p.expectPunct(")")
+}
+
+// parseWindowFrame 解析 ROWS|RANGE|GROUPS [BETWEEN] start [AND end]
+func (p *Parser) parseWindowFrame() (*ast.WindowFrame, error) {
+	tok := p.next()
+	frame := &ast.WindowFrame{Pos: tok.Start, Units: ast.Keyword(tok.Value)}
+
+	between, ok := p.acceptKeyword("BETWEEN")
+	var err error
+	if frame.Start, err = p.parseFrameBound(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return frame, nil
+	}
+	frame.Between = between
+	if frame.And, err = p.expectKeyword("AND"); err != nil {
+		return nil, err
+	}
+	if frame.End, err = p.parseFrameBound(); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// parseFrameBound 解析窗口边界
+func (p *Parser) parseFrameBound() (*ast.FrameBound, error) {
+	for _, words := range [][]string{
+		{"UNBOUNDED", "PRECEDING"},
+		{"UNBOUNDED", "FOLLOWING"},
+		{"CURRENT", "ROW"},
+	} {
+		if kw, ok := p.acceptKeyword(words...); ok {
+			return &ast.FrameBound{Bound: kw}, nil
+		}
+	}
+
+	offset, err := p.parseBitwise()
+	if err != nil {
+		return nil, err
+	}
+	bound := &ast.FrameBound{Offset: offset}
+	if kw, ok := p.acceptKeyword("PRECEDING"); ok {
+		bound.Bound = kw
+	} else if bound.Bound, err = p.expectKeyword("FOLLOWING"); err != nil {
+		return nil, err
+	}
+	return bound, nil
+}
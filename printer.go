@@ -0,0 +1,699 @@
+package sqlformatter
+
+import (
+	"math"
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// printer 将语法树输出为格式化文本，并在换行处插入原文中的注释
+type printer struct {
+	*Formatter
+	comments []*ast.Comment // 尚未输出的注释
+}
+
+// newPrinter 创建输出器
+func newPrinter(f *Formatter, comments []*ast.Comment) *printer {
+	return &printer{Formatter: f, comments: comments}
+}
+
+// hasCommentBefore 是否还有位于pos之前的注释未输出
+func (p *printer) hasCommentBefore(pos int) bool {
+	return len(p.comments) > 0 && p.comments[0].Pos < pos
+}
+
+// nextComment 取出下一条注释
+func (p *printer) nextComment() *ast.Comment {
+	c := p.comments[0]
+	p.comments = p.comments[1:]
+	return c
+}
+
+// newline 换行到指定缩进级别，pos为下一行内容在原文中的位置；
+// 其之前的注释中，行尾注释追加到当前行，独占一行的注释单独输出
+func (p *printer) newline(level int, pos int) string {
+	indent := p.getIndent(level)
+	var result strings.Builder
+	lineComment := false
+	for p.hasCommentBefore(pos) {
+		c := p.nextComment()
+		if c.Trailing && !lineComment {
+			result.WriteString(" " + c.Text)
+		} else {
+			result.WriteString("\n" + indent + c.Text)
+		}
+		lineComment = c.IsLine()
+	}
+	result.WriteString("\n" + indent)
+	return result.String()
+}
+
+// trailingComments 输出语句末尾pos之前的行尾注释
+func (p *printer) trailingComments(pos int) string {
+	var result strings.Builder
+	for p.hasCommentBefore(pos) && p.comments[0].Trailing {
+		c := p.nextComment()
+		result.WriteString(" " + c.Text)
+		if c.IsLine() {
+			break
+		}
+	}
+	return result.String()
+}
+
+// leadingComments 输出语句之前pos之前的注释，每行之后换行
+func (p *printer) leadingComments(pos int) string {
+	var result strings.Builder
+	lineComment := true
+	for p.hasCommentBefore(pos) {
+		c := p.nextComment()
+		if c.Trailing && !lineComment {
+			result.WriteString(" " + c.Text)
+		} else {
+			if result.Len() > 0 {
+				result.WriteString("\n")
+			}
+			result.WriteString(c.Text)
+		}
+		lineComment = c.IsLine()
+	}
+	if result.Len() > 0 {
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// restComments 输出语句之后剩余的全部注释
+func (p *printer) restComments() string {
+	result := p.trailingComments(math.MaxInt)
+	if rest := p.leadingComments(math.MaxInt); rest != "" {
+		result += "\n" + strings.TrimSuffix(rest, "\n")
+	}
+	return result
+}
+
+// formatSQL 格式化SQL语句，level为语句首行的缩进级别
+func (p *printer) formatSQL(stmt ast.Statement, level int) string {
+	// 按语句类型格式化
+	switch stmt := stmt.(type) {
+	case *ast.WithStmt:
+		return p.formatWithStatement(stmt, level)
+	case *ast.SelectStmt:
+		return p.formatSelectStatement(stmt, level)
+	case *ast.SetOpStmt:
+		return p.formatSetOpStatement(stmt, level)
+	case *ast.ParenStmt:
+		return "(" + p.newline(level+1, ast.Pos(stmt.Stmt)) + p.formatSQL(stmt.Stmt, level+1) +
+			p.newline(level, stmt.Rparen) + ")"
+	case *ast.InsertStmt:
+		return p.formatInsertStatement(stmt, level)
+	case *ast.UpdateStmt:
+		return p.formatUpdateStatement(stmt, level)
+	case *ast.DeleteStmt:
+		return p.formatDeleteStatement(stmt, level)
+	case *ast.MergeStmt:
+		return p.formatMergeStatement(stmt, level)
+	case *ast.CreateTableStmt:
+		return p.formatCreateTableStatement(stmt, level)
+	case *ast.AlterTableStmt:
+		return p.formatAlterTableStatement(stmt, level)
+	case *ast.CreateIndexStmt:
+		return p.formatCreateIndexStatement(stmt, level)
+	case *ast.CreateViewStmt:
+		return p.formatCreateViewStatement(stmt, level)
+	case *ast.SequenceStmt:
+		return p.formatSequenceStatement(stmt, level)
+	case *ast.CreateSchemaStmt:
+		return p.formatCreateSchemaStatement(stmt)
+	case *ast.DropStmt:
+		return p.formatDropStatement(stmt)
+	case *ast.CreateVirtualTableStmt:
+		return p.formatCreateVirtualTableStatement(stmt, level)
+	case *ast.PragmaStmt:
+		return p.formatPragmaStatement(stmt)
+	case *ast.AttachStmt:
+		return p.formatAttachStatement(stmt, level)
+	case *ast.DetachStmt:
+		return p.keyword(string(stmt.Detach)) + " " + stmt.Schema
+	case *ast.DeclareStmt:
+		return p.formatDeclareStatement(stmt, level)
+	case *ast.SetStmt:
+		return p.formatSetStatement(stmt, level)
+	case *ast.BlockStmt:
+		return p.formatBlockStatement(stmt, level)
+	case *ast.IfStmt:
+		return p.formatIfStatement(stmt, level)
+	case *ast.CaseStmt:
+		return p.formatCaseStatement(stmt, level)
+	case *ast.LoopStmt:
+		return p.formatLoopStatement(stmt, level)
+	case *ast.RawStmt:
+		return stmt.Text
+	}
+
+	return ""
+}
+
+// formatWithStatement 格式化WITH语句，每个公用表表达式各占一块，查询体缩进一级
+func (p *printer) formatWithStatement(stmt *ast.WithStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("WITH"))
+	if stmt.Recursive != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Recursive)))
+	}
+	for i, cte := range stmt.CTEs {
+		if i > 0 {
+			result.WriteString("," + p.newline(level, ast.Pos(cte)))
+		} else {
+			result.WriteString(" ")
+		}
+		result.WriteString(cte.Name.String())
+		if len(cte.Columns) > 0 {
+			result.WriteString(" (" + p.formatNames(cte.Columns) + ")")
+		}
+		result.WriteString(" " + string(cte.As))
+		if cte.Materialized != "" {
+			result.WriteString(" " + string(cte.Materialized))
+		}
+		result.WriteString(" " + p.formatSubquery(cte.Subquery, level))
+	}
+
+	// 主语句
+	result.WriteString(p.newline(level, ast.Pos(stmt.Stmt)))
+	result.WriteString(p.formatSQL(stmt.Stmt, level))
+
+	return result.String()
+}
+
+// formatSelectStatement 格式化SELECT语句
+func (p *printer) formatSelectStatement(stmt *ast.SelectStmt, level int) string {
+	var result strings.Builder
+
+	// SELECT部分
+	result.WriteString(p.keyword("SELECT"))
+	if stmt.Distinct != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Distinct)))
+	}
+	if len(stmt.DistinctOn) > 0 {
+		result.WriteString(" (" + p.formatExprList(stmt.DistinctOn, level+1) + ")")
+	}
+	if stmt.Top != nil {
+		result.WriteString(" " + p.formatTop(stmt.Top, level+1))
+	}
+	result.WriteString(p.newline(level+1, ast.Pos(stmt.Columns[0])))
+	result.WriteString(p.formatSelectColumns(stmt.Columns, level+1))
+
+	// INTO部分
+	if len(stmt.Into) > 0 {
+		result.WriteString(p.newline(level, ast.Pos(stmt.Into[0])) + p.keyword("INTO"))
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.Into[0])))
+		result.WriteString(p.formatExprList(stmt.Into, level+1))
+	}
+
+	// FROM部分
+	result.WriteString(p.formatTableClause("FROM", stmt.From, level))
+
+	// WHERE部分
+	if stmt.Where != nil {
+		result.WriteString(p.formatConditionClause("WHERE", stmt.Where, level))
+	}
+
+	// START WITH与CONNECT BY部分
+	if stmt.Hierarchy != nil {
+		result.WriteString(p.formatHierarchy(stmt.Hierarchy, level))
+	}
+
+	// GROUP BY部分
+	if len(stmt.GroupBy) > 0 {
+		result.WriteString(p.newline(level, ast.Pos(stmt.GroupBy[0])) + p.keyword("GROUP BY"))
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.GroupBy[0])))
+		result.WriteString(p.formatExprList(stmt.GroupBy, level+1))
+	}
+
+	// HAVING部分
+	if stmt.Having != nil {
+		result.WriteString(p.formatConditionClause("HAVING", stmt.Having, level))
+	}
+
+	// WINDOW部分
+	if len(stmt.Window) > 0 {
+		result.WriteString(p.newline(level, ast.Pos(stmt.Window[0])) + p.keyword("WINDOW"))
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.Window[0])))
+		result.WriteString(p.formatWindowDefs(stmt.Window, level+1))
+	}
+
+	// ORDER BY和LIMIT部分，层次查询的ORDER SIBLINGS BY保留SIBLINGS
+	if stmt.Siblings != "" {
+		result.WriteString(p.newline(level, ast.Pos(stmt.OrderBy[0])) + p.keyword(string(stmt.Siblings)))
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.OrderBy[0])))
+		result.WriteString(p.formatOrderBy(stmt.OrderBy, level+1))
+		result.WriteString(p.formatOrderLimit(nil, stmt.Limit, level))
+	} else {
+		result.WriteString(p.formatOrderLimit(stmt.OrderBy, stmt.Limit, level))
+	}
+
+	return result.String()
+}
+
+// formatHierarchy 格式化层次查询子句，START WITH与CONNECT BY保持原来的先后顺序
+func (p *printer) formatHierarchy(clause *ast.Hierarchy, level int) string {
+	connectBy := p.formatConditionClause(string(clause.ConnectBy), clause.Cond, level)
+	if clause.StartWith == nil {
+		return connectBy
+	}
+	startWith := p.formatConditionClause("START WITH", clause.StartWith, level)
+	if clause.StartFirst {
+		return startWith + connectBy
+	}
+	return connectBy + startWith
+}
+
+// formatTop 格式化 TOP (n) [PERCENT] [WITH TIES]
+func (p *printer) formatTop(top *ast.Top, level int) string {
+	result := p.keyword(string(top.Top)) + " " + p.formatExpr(top.Count, level)
+	if top.Percent != "" {
+		result += " " + p.keyword(string(top.Percent))
+	}
+	if top.WithTies != "" {
+		result += " " + p.keyword(string(top.WithTies))
+	}
+	return result
+}
+
+// formatWindowDefs 格式化WINDOW子句中的命名窗口
+func (p *printer) formatWindowDefs(defs []*ast.WindowDef, level int) string {
+	var result strings.Builder
+	for i, def := range defs {
+		if i > 0 {
+			result.WriteString("," + p.newline(level, ast.Pos(def)))
+		}
+		result.WriteString(def.Name.String() + " " + string(def.As) + " " + p.formatWindowSpec(def.Spec, level))
+	}
+	return result.String()
+}
+
+// formatSetOpStatement 格式化集合运算，运算符独占一行
+func (p *printer) formatSetOpStatement(stmt *ast.SetOpStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.formatSQL(stmt.Left, level))
+	pos := ast.Pos(stmt.Right)
+	result.WriteString(p.newline(level, pos) + p.keyword(string(stmt.Op)))
+	result.WriteString(p.newline(level, pos) + p.formatSQL(stmt.Right, level))
+
+	// 作用于整个结果的ORDER BY和LIMIT
+	result.WriteString(p.formatOrderLimit(stmt.OrderBy, stmt.Limit, level))
+
+	return result.String()
+}
+
+// formatOrderLimit 格式化ORDER BY和LIMIT/OFFSET子句
+func (p *printer) formatOrderLimit(orderBy []*ast.OrderItem, limit *ast.Limit, level int) string {
+	var result strings.Builder
+	if len(orderBy) > 0 {
+		result.WriteString(p.newline(level, ast.Pos(orderBy[0])) + p.keyword("ORDER BY"))
+		result.WriteString(p.newline(level+1, ast.Pos(orderBy[0])))
+		result.WriteString(p.formatOrderBy(orderBy, level+1))
+	}
+	if limit != nil {
+		result.WriteString(p.formatLimit(limit, level))
+	}
+	return result.String()
+}
+
+// formatClause 格式化关键字独占一行、内容缩进一级的子句
+func (p *printer) formatClause(keyword string, expr ast.Expr, level int) string {
+	pos := ast.Pos(expr)
+	return p.newline(level, pos) + p.keyword(keyword) + p.newline(level+1, pos) + p.formatExpr(expr, level+1)
+}
+
+// formatConditionClause 格式化WHERE/HAVING子句，条件在AND/OR处换行
+func (p *printer) formatConditionClause(keyword string, expr ast.Expr, level int) string {
+	pos := ast.Pos(expr)
+	return p.newline(level, pos) + p.keyword(keyword) + p.newline(level+1, pos) + p.formatCondition(expr, level+1)
+}
+
+// formatSelectColumns 格式化SELECT列
+func (p *printer) formatSelectColumns(columns []*ast.SelectItem, level int) string {
+	var result strings.Builder
+	for i, col := range columns {
+		if i > 0 {
+			result.WriteString("," + p.newline(level, ast.Pos(col)))
+		}
+		result.WriteString(p.formatSelectItem(col, level))
+	}
+
+	return result.String()
+}
+
+// formatSelectItem 格式化单个选择项
+func (p *printer) formatSelectItem(item *ast.SelectItem, level int) string {
+	return p.formatExpr(item.Expr, level) + p.formatAlias(item.As, item.Alias)
+}
+
+// formatAlias 格式化别名，保留AS的原文
+func (p *printer) formatAlias(as ast.Keyword, alias string) string {
+	switch {
+	case alias == "":
+		return ""
+	case as != "":
+		return " " + string(as) + " " + alias
+	}
+	return " " + alias
+}
+
+// formatOrderBy 格式化ORDER BY列表
+func (p *printer) formatOrderBy(items []*ast.OrderItem, level int) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = p.formatExpr(item.Expr, level)
+		if item.Direction != "" {
+			parts[i] += " " + string(item.Direction)
+		}
+		if item.Nulls != "" {
+			parts[i] += " " + string(item.Nulls)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatLimit 格式化LIMIT/OFFSET子句，或OFFSET ... ROWS FETCH ...子句
+func (p *printer) formatLimit(limit *ast.Limit, level int) string {
+	if limit.Comma {
+		pos := ast.Pos(limit.Offset)
+		return p.newline(level, pos) + p.keyword("LIMIT") + p.newline(level+1, pos) +
+			p.formatExpr(limit.Offset, level+1) + ", " + p.formatExpr(limit.Count, level+1)
+	}
+	var result strings.Builder
+	if limit.Count != nil && limit.Fetch == "" {
+		result.WriteString(p.formatClause("LIMIT", limit.Count, level))
+	}
+	if limit.Offset != nil {
+		result.WriteString(p.formatClause("OFFSET", limit.Offset, level))
+		if limit.OffsetRows != "" {
+			result.WriteString(" " + p.keyword(string(limit.OffsetRows)))
+		}
+	}
+	if limit.Fetch != "" {
+		result.WriteString(p.newline(level, -1) + p.keyword(string(limit.Fetch)) + p.newline(level+1, -1))
+		if limit.Count != nil {
+			result.WriteString(p.formatExpr(limit.Count, level+1) + " ")
+		}
+		result.WriteString(p.keyword(string(limit.FetchRows)))
+	}
+	return result.String()
+}
+
+// formatFromClause 格式化FROM子句
+func (p *printer) formatFromClause(tables []ast.TableExpr, level int) string {
+	var result strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			result.WriteString("," + p.newline(level, ast.Pos(table)))
+		}
+		result.WriteString(p.formatTableExpr(table, level))
+	}
+	return result.String()
+}
+
+// formatTableClause 格式化FROM、USING等以表列表为内容的子句，关键字独占一行；tables为空时返回空串
+func (p *printer) formatTableClause(keyword string, tables []ast.TableExpr, level int) string {
+	if len(tables) == 0 {
+		return ""
+	}
+	pos := ast.Pos(tables[0])
+	return p.newline(level, pos) + p.keyword(keyword) + p.newline(level+1, pos) + p.formatFromClause(tables, level+1)
+}
+
+// formatTableExpr 格式化表表达式，连接各占一行并缩进到level级
+func (p *printer) formatTableExpr(table ast.TableExpr, level int) string {
+	switch table := table.(type) {
+	case *ast.TableName:
+		return table.Name.String() + p.formatAlias(table.As, table.Alias) + p.formatIndexHints(table.Hints)
+	case *ast.DerivedTable:
+		return p.formatLateral(table.Lateral) + p.formatExpr(table.Subquery, level) +
+			p.formatTableAlias(table.As, table.Alias, table.Columns)
+	case *ast.FuncTable:
+		return p.formatLateral(table.Lateral) + p.formatExpr(table.Func, level) +
+			p.formatTableAlias(table.As, table.Alias, table.Columns)
+	case *ast.ParenTable:
+		return "(" + p.newline(level+1, ast.Pos(table.Table)) + p.formatTableExpr(table.Table, level+1) +
+			p.newline(level, table.Rparen) + ")"
+	case *ast.JoinExpr:
+		result := p.formatTableExpr(table.Left, level)
+		result += p.newline(level, ast.Pos(table.Right))
+		result += p.keyword(string(table.Type)) + " " + p.formatTableExpr(table.Right, level)
+		if table.Condition != nil {
+			result += p.formatJoinCondition(table.Condition, level)
+		}
+		return result
+	}
+	return ""
+}
+
+// formatIndexHints 格式化表名之后的索引提示
+func (p *printer) formatIndexHints(hints []*ast.IndexHint) string {
+	var result strings.Builder
+	for _, hint := range hints {
+		result.WriteString(" " + p.keyword(string(hint.Hint)) + " (")
+		if hint.Indexes != nil {
+			result.WriteString(hint.Indexes.Text)
+		}
+		result.WriteString(")")
+	}
+	return result.String()
+}
+
+// formatTableAlias 格式化别名及其后的列名列表
+func (p *printer) formatTableAlias(as ast.Keyword, alias string, columns []*ast.Name) string {
+	if len(columns) == 0 {
+		return p.formatAlias(as, alias)
+	}
+	return p.formatAlias(as, alias) + " (" + p.formatNames(columns) + ")"
+}
+
+// formatLateral 格式化LATERAL修饰
+func (p *printer) formatLateral(lateral ast.Keyword) string {
+	if lateral == "" {
+		return ""
+	}
+	return string(lateral) + " "
+}
+
+// formatJoinCondition 格式化ON/USING条件，可独占一行并缩进一级，ON条件可在AND/OR处换行
+func (p *printer) formatJoinCondition(cond *ast.JoinCondition, level int) string {
+	result := " "
+	if p.JoinConditionNewline {
+		result = p.newline(level+1, -1)
+	}
+	result += string(cond.Keyword)
+	switch {
+	case cond.On == nil:
+		result += " (" + p.formatNames(cond.Using) + ")"
+	case p.SplitJoinConditions:
+		result += " " + p.formatCondition(cond.On, level+1)
+	default:
+		result += " " + p.formatExpr(cond.On, level)
+	}
+	return result
+}
+
+// formatInsertStatement 格式化INSERT语句
+func (p *printer) formatInsertStatement(stmt *ast.InsertStmt, level int) string {
+	var result strings.Builder
+
+	// INSERT INTO table (col1, col2) VALUES (val1, val2)
+	result.WriteString(p.keyword(string(stmt.Insert)) + " " + p.keyword("INTO") + " " + stmt.Table.String())
+	if len(stmt.Columns) > 0 {
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.Columns[0])))
+		result.WriteString("(" + p.formatNames(stmt.Columns) + ")")
+	}
+	result.WriteString(p.formatReturning(stmt.Output, level))
+
+	switch {
+	case stmt.DefaultValues != "":
+		result.WriteString(p.newline(level, -1) + p.keyword(string(stmt.DefaultValues)))
+	case stmt.Query != nil:
+		result.WriteString(p.newline(level, ast.Pos(stmt.Query)) + p.formatSQL(stmt.Query, level))
+	default:
+		// 多行插入时每行占一行
+		result.WriteString(p.newline(level, ast.Pos(stmt.Values[0][0])) + p.keyword("VALUES"))
+		for i, row := range stmt.Values {
+			if i > 0 {
+				result.WriteString(",")
+			}
+			result.WriteString(p.newline(level+1, ast.Pos(row[0])))
+			result.WriteString("(" + p.formatExprList(row, level+1) + ")")
+		}
+	}
+
+	if stmt.OnConflict != nil {
+		result.WriteString(p.formatOnConflict(stmt.OnConflict, level))
+	}
+	result.WriteString(p.formatReturning(stmt.Returning, level))
+
+	return result.String()
+}
+
+// formatOnConflict 格式化ON CONFLICT或ON DUPLICATE KEY UPDATE子句，赋值与UPDATE的SET子句一样缩进
+func (p *printer) formatOnConflict(clause *ast.OnConflict, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.newline(level, clause.Pos) + p.keyword(string(clause.Clause)))
+	if clause.Target != nil {
+		result.WriteString(" (" + clause.Target.Text + ")")
+	}
+	if clause.TargetWhere != nil {
+		result.WriteString(" " + p.keyword("WHERE") + " " + p.formatExpr(clause.TargetWhere, level))
+	}
+	if clause.Constraint != nil {
+		result.WriteString(" " + p.keyword(string(clause.OnConstraint)) + " " + clause.Constraint.String())
+	}
+	if clause.Action != "" {
+		result.WriteString(" " + p.keyword(string(clause.Action)))
+	}
+	if len(clause.Set) > 0 {
+		result.WriteString(p.newline(level+1, ast.Pos(clause.Set[0])) + p.formatSetClause(clause.Set, level+1))
+	}
+	if clause.Where != nil {
+		result.WriteString(p.formatConditionClause("WHERE", clause.Where, level))
+	}
+
+	return result.String()
+}
+
+// formatUpdateStatement 格式化UPDATE语句
+func (p *printer) formatUpdateStatement(stmt *ast.UpdateStmt, level int) string {
+	var result strings.Builder
+
+	// UPDATE部分，带连接时与FROM子句的格式相同
+	result.WriteString(p.keyword("UPDATE"))
+	if stmt.Conflict != "" {
+		result.WriteString(" " + p.keyword(string(stmt.Conflict)))
+	}
+	if _, ok := stmt.Table.(*ast.JoinExpr); ok {
+		result.WriteString(p.newline(level+1, ast.Pos(stmt.Table)) + p.formatTableExpr(stmt.Table, level+1))
+	} else {
+		result.WriteString(" " + p.formatTableExpr(stmt.Table, level))
+	}
+
+	// SET部分
+	result.WriteString(p.newline(level, ast.Pos(stmt.Set[0])) + p.keyword("SET"))
+	result.WriteString(p.newline(level+1, ast.Pos(stmt.Set[0])) + p.formatSetClause(stmt.Set, level+1))
+	result.WriteString(p.formatReturning(stmt.Output, level))
+	result.WriteString(p.formatTableClause("FROM", stmt.From, level))
+
+	// WHERE、ORDER BY和LIMIT部分
+	if stmt.Where != nil {
+		result.WriteString(p.formatConditionClause("WHERE", stmt.Where, level))
+	}
+	result.WriteString(p.formatOrderLimit(stmt.OrderBy, stmt.Limit, level))
+	result.WriteString(p.formatReturning(stmt.Returning, level))
+
+	return result.String()
+}
+
+// formatDeleteStatement 格式化DELETE语句
+func (p *printer) formatDeleteStatement(stmt *ast.DeleteStmt, level int) string {
+	var result strings.Builder
+
+	// DELETE FROM部分，多表删除时先列出要删除的表
+	if len(stmt.Targets) > 0 {
+		result.WriteString(p.keyword("DELETE") + " " + p.formatNames(stmt.Targets))
+	} else {
+		result.WriteString(p.keyword("DELETE FROM") + " " + p.formatTableExpr(stmt.Table, level))
+	}
+	result.WriteString(p.formatReturning(stmt.Output, level))
+	result.WriteString(p.formatTableClause("FROM", stmt.From, level))
+	result.WriteString(p.formatTableClause("USING", stmt.Using, level))
+
+	// WHERE、ORDER BY和LIMIT部分
+	if stmt.Where != nil {
+		result.WriteString(p.formatConditionClause("WHERE", stmt.Where, level))
+	}
+	result.WriteString(p.formatOrderLimit(stmt.OrderBy, stmt.Limit, level))
+	result.WriteString(p.formatReturning(stmt.Returning, level))
+
+	return result.String()
+}
+
+// formatMergeStatement 格式化MERGE语句，每个WHEN分支各占一块，分支中的操作缩进一级
+func (p *printer) formatMergeStatement(stmt *ast.MergeStmt, level int) string {
+	var result strings.Builder
+
+	result.WriteString(p.keyword("MERGE INTO") + " " + p.formatTableExpr(stmt.Target, level))
+	result.WriteString(p.newline(level, ast.Pos(stmt.Source)) + p.keyword("USING") + " " + p.formatTableExpr(stmt.Source, level))
+	result.WriteString(p.newline(level, ast.Pos(stmt.On)) + p.keyword("ON") + " " + p.formatExpr(stmt.On, level))
+
+	for _, when := range stmt.Whens {
+		result.WriteString(p.newline(level, when.Pos) + p.keyword("WHEN") + " " + p.keyword(string(when.Match)))
+		if when.Cond != nil {
+			result.WriteString(" " + p.keyword("AND") + " " + p.formatExpr(when.Cond, level+1))
+		}
+		result.WriteString(" " + p.keyword("THEN"))
+		result.WriteString(p.newline(level+1, -1) + p.keyword(string(when.Action)))
+		switch {
+		case len(when.Set) > 0:
+			result.WriteString(p.newline(level+2, ast.Pos(when.Set[0])) + p.formatSetClause(when.Set, level+2))
+		case when.DefaultValues != "":
+			if len(when.Columns) > 0 {
+				result.WriteString(" (" + p.formatNames(when.Columns) + ")")
+			}
+			result.WriteString(" " + p.keyword(string(when.DefaultValues)))
+		case when.Values != nil:
+			if len(when.Columns) > 0 {
+				result.WriteString(" (" + p.formatNames(when.Columns) + ")")
+			}
+			result.WriteString(p.newline(level+1, ast.Pos(when.Values[0])) + p.keyword("VALUES"))
+			result.WriteString(" (" + p.formatExprList(when.Values, level+1) + ")")
+		}
+	}
+
+	result.WriteString(p.formatReturning(stmt.Output, level))
+	result.WriteString(p.formatReturning(stmt.Returning, level))
+
+	return result.String()
+}
+
+// formatReturning 格式化RETURNING或OUTPUT子句，列表与SELECT列表的格式相同；clause为nil时返回空串
+func (p *printer) formatReturning(clause *ast.ReturningClause, level int) string {
+	if clause == nil {
+		return ""
+	}
+	var result strings.Builder
+
+	result.WriteString(p.newline(level, clause.Pos) + p.keyword(string(clause.Keyword)))
+	result.WriteString(p.newline(level+1, ast.Pos(clause.Columns[0])))
+	result.WriteString(p.formatSelectColumns(clause.Columns, level+1))
+	if clause.Target != nil {
+		result.WriteString(p.newline(level, ast.Pos(clause.Target)) + p.keyword(string(clause.Into)) + " " + clause.Target.String())
+		if len(clause.TargetNames) > 0 {
+			result.WriteString(" (" + p.formatNames(clause.TargetNames) + ")")
+		}
+	}
+
+	return result.String()
+}
+
+// formatNames 格式化名称列表
+func (p *printer) formatNames(names []*ast.Name) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatSetClause 格式化SET子句
+func (p *printer) formatSetClause(assignments []*ast.Assignment, level int) string {
+	var result strings.Builder
+	for i, assignment := range assignments {
+		if i > 0 {
+			result.WriteString("," + p.newline(level, ast.Pos(assignment)))
+		}
+		result.WriteString(assignment.Column.String() + " = " + p.formatExpr(assignment.Value, level))
+	}
+	return result.String()
+}
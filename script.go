@@ -0,0 +1,143 @@
+package sqlformatter
+
+import (
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/ast"
+)
+
+// formatDeclareStatement 格式化DECLARE语句，多个变量时每个变量独占一行并缩进一级
+func (p *printer) formatDeclareStatement(stmt *ast.DeclareStmt, level int) string {
+	var result strings.Builder
+	result.WriteString(p.keyword(string(stmt.Declare)))
+	for i, decl := range stmt.Vars {
+		switch {
+		case i > 0:
+			result.WriteString("," + p.newline(level+1, decl.Pos))
+		case len(stmt.Vars) > 1:
+			result.WriteString(p.newline(level+1, decl.Pos))
+		default:
+			result.WriteString(" ")
+		}
+		result.WriteString(p.formatVarDecl(decl, level+1))
+	}
+	return result.String()
+}
+
+// formatVarDecl 格式化单个变量声明
+func (p *printer) formatVarDecl(decl *ast.VarDecl, level int) string {
+	result := decl.Name
+	if decl.As != "" {
+		result += " " + p.keyword(string(decl.As))
+	}
+	result += " " + decl.Type.Text
+	if decl.Value != nil {
+		result += " = " + p.formatExpr(decl.Value, level)
+	}
+	return result
+}
+
+// formatSetStatement 格式化SET语句，会话选项保留原文
+func (p *printer) formatSetStatement(stmt *ast.SetStmt, level int) string {
+	if stmt.Option != nil {
+		return p.keyword(string(stmt.Set)) + " " + stmt.Option.Text
+	}
+	return p.keyword(string(stmt.Set)) + " " + stmt.Name + " " + stmt.Op + " " + p.formatExpr(stmt.Value, level)
+}
+
+// formatBlockStatement 格式化过程块，声明、语句与异常处理分支缩进一级
+func (p *printer) formatBlockStatement(block *ast.BlockStmt, level int) string {
+	var result strings.Builder
+	if block.Declare != "" {
+		result.WriteString(p.keyword(string(block.Declare)))
+		for _, decl := range block.Decls {
+			result.WriteString(p.newline(level+1, decl.Pos) + decl.Text + ";")
+		}
+		result.WriteString(p.newline(level, block.BeginPos))
+	}
+	result.WriteString(p.keyword(string(block.Begin)))
+	result.WriteString(p.formatBlockBody(block.Body, level+1))
+	if block.Exception != "" {
+		result.WriteString(p.newline(level, block.ExceptionPos) + p.keyword(string(block.Exception)))
+		for _, handler := range block.Handlers {
+			result.WriteString(p.newline(level+1, handler.Pos) + p.keyword(string(handler.When)) + " " +
+				handler.Names.Text + " " + p.keyword(string(handler.Then)))
+			result.WriteString(p.formatBlockBody(handler.Body, level+2))
+		}
+	}
+	result.WriteString(p.newline(level, block.EndPos) + p.keyword(string(block.End)))
+	if block.Label != "" {
+		result.WriteString(" " + block.Label)
+	}
+	return result.String()
+}
+
+// formatIfStatement 格式化IF语句，ELSIF、ELSE与END IF和IF对齐，各分支的语句缩进一级
+func (p *printer) formatIfStatement(stmt *ast.IfStmt, level int) string {
+	var result strings.Builder
+	for i, branch := range stmt.Branches {
+		if i > 0 {
+			result.WriteString(p.newline(level, branch.Pos))
+		}
+		result.WriteString(p.formatBranch(branch, level+1))
+	}
+	if stmt.Else != "" {
+		result.WriteString(p.newline(level, stmt.ElsePos) + p.keyword(string(stmt.Else)))
+		result.WriteString(p.formatBlockBody(stmt.ElseBody, level+1))
+	}
+	result.WriteString(p.newline(level, stmt.EndPos) + p.keyword(string(stmt.End)))
+	return result.String()
+}
+
+// formatCaseStatement 格式化CASE语句，WHEN和ELSE缩进一级，其中的语句再缩进一级
+func (p *printer) formatCaseStatement(stmt *ast.CaseStmt, level int) string {
+	var result strings.Builder
+	result.WriteString(p.keyword(string(stmt.Case)))
+	if stmt.Operand != nil {
+		result.WriteString(" " + stmt.Operand.Text)
+	}
+	for _, when := range stmt.Whens {
+		result.WriteString(p.newline(level+1, when.Pos) + p.formatBranch(when, level+2))
+	}
+	if stmt.Else != "" {
+		result.WriteString(p.newline(level+1, stmt.ElsePos) + p.keyword(string(stmt.Else)))
+		result.WriteString(p.formatBlockBody(stmt.ElseBody, level+2))
+	}
+	result.WriteString(p.newline(level, stmt.EndPos) + p.keyword(string(stmt.End)))
+	return result.String()
+}
+
+// formatBranch 格式化 cond THEN stmts 分支，bodyLevel为分支内语句的缩进级别
+func (p *printer) formatBranch(branch *ast.Branch, bodyLevel int) string {
+	return p.keyword(string(branch.Word)) + " " + branch.Cond.Text + " " + p.keyword(string(branch.Then)) +
+		p.formatBlockBody(branch.Body, bodyLevel)
+}
+
+// formatLoopStatement 格式化循环语句，循环体缩进一级，END LOOP与LOOP所在行对齐
+func (p *printer) formatLoopStatement(stmt *ast.LoopStmt, level int) string {
+	var result strings.Builder
+	if stmt.Head != nil {
+		head := stmt.Head.Text
+		// WHILE与FOR是循环头的第一个单词
+		if i := strings.IndexByte(head, ' '); i > 0 {
+			head = p.keyword(head[:i]) + head[i:]
+		}
+		result.WriteString(head + " ")
+	}
+	result.WriteString(p.keyword(string(stmt.Loop)))
+	result.WriteString(p.formatBlockBody(stmt.Body, level+1))
+	result.WriteString(p.newline(level, stmt.EndPos) + p.keyword(string(stmt.End)))
+	if stmt.Label != "" {
+		result.WriteString(" " + stmt.Label)
+	}
+	return result.String()
+}
+
+// formatBlockBody 格式化过程块中的语句，每条语句另起一行并以分号结尾
+func (p *printer) formatBlockBody(stmts []ast.Statement, level int) string {
+	var result strings.Builder
+	for _, stmt := range stmts {
+		result.WriteString(p.newline(level, ast.Pos(stmt)) + p.formatSQL(stmt, level) + ";")
+	}
+	return result.String()
+}
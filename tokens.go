@@ -0,0 +1,35 @@
+package sqlformatter
+
+import (
+	"strings"
+
+	"github.com/BruceDu521/sql-formatter/lexer"
+)
+
+// trimTokens 去掉首尾的空白词法单元
+func trimTokens(tokens []lexer.Token) []lexer.Token {
+	start, end := 0, len(tokens)
+	for start < end && tokens[start].Type == lexer.Whitespace {
+		start++
+	}
+	for end > start && tokens[end-1].Type == lexer.Whitespace {
+		end--
+	}
+	return tokens[start:end]
+}
+
+// parenDelta 返回词法单元对括号深度的影响
+func parenDelta(tok lexer.Token) int {
+	switch {
+	case tok.IsPunct("("):
+		return 1
+	case tok.IsPunct(")"):
+		return -1
+	}
+	return 0
+}
+
+// isLineComment 是否为行注释；词法分析器已按方言识别注释，除块注释外都延续到行尾
+func isLineComment(tok lexer.Token) bool {
+	return tok.Type == lexer.Comment && !strings.HasPrefix(tok.Value, "/*")
+}